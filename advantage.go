@@ -0,0 +1,54 @@
+package elo
+
+// Set an advantage in elo for player one, such as home field, the white pieces, or
+// serving first. The advantage is added to player one's elo when calculating expected
+// scores and odds, by the elo and Glicko strategies alike, but is never added to their
+// stored rating. A negative value favors player two. Default is 0.
+func (b *CalculatorBuilder) WithAdvantage(a float64) *CalculatorBuilder {
+	b.c.advantage = a
+	return b
+}
+
+// A historical result used to estimate an advantage, where PlayerOne is the elo of the
+// player who had the advantage at the time of the match.
+type AdvantageSample struct {
+	PlayerOne float64
+	PlayerTwo float64
+	Outcome   MatchOutcome
+}
+
+// Estimates the advantage in elo held by player one from historical results, using the
+// calculator's deviation and draw parameter. The estimate is the advantage under which
+// player one's expected score across all samples equals the score they actually got.
+// The estimate is limited to twice the deviation in either direction, which is reached
+// when player one won or lost every sample. Returns 0 if there are no samples.
+func (c *Calculator) EstimateAdvantage(samples []AdvantageSample) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	// the total difference between actual and expected score falls as the advantage grows
+	surplus := func(a float64) float64 {
+		var sum float64
+		for _, s := range samples {
+			odds := davidsonOdds(s.PlayerOne+a, s.PlayerTwo, c.deviation, c.drawParameter)
+			S1, _ := outcomeScores(s.Outcome)
+			sum += S1 - odds.PlayerOneOdds - odds.DrawOdds/2
+		}
+		return sum
+	}
+	return bisect(surplus, -2*c.deviation, 2*c.deviation)
+}
+
+// Returns the root of the decreasing function f between lo and hi, or the bound closest
+// to it if f does not change sign in the range.
+func bisect(f func(float64) float64, lo, hi float64) float64 {
+	for i := 0; i < 100 && hi-lo > 1e-9; i++ {
+		mid := (lo + hi) / 2
+		if f(mid) > 0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
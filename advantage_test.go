@@ -0,0 +1,114 @@
+package elo_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+func TestAdvantage(t *testing.T) {
+	c := elo.NewCalculatorBuilder().
+		WithAdvantage(100).
+		Build()
+
+	p1 := &player{1500}
+	p2 := &player{1500}
+
+	m := c.NewMatch(p1, p2)
+	o := m.GetOdds()
+	if !almostEqual(o.PlayerOneOdds, 0.640065) {
+		t.Fail()
+		t.Logf("Expected P1 odds %f, got %f\n", 0.640065, o.PlayerOneOdds)
+	}
+	m.Play(&elo.MatchResult{
+		Outcome: elo.OutcomePlayerOneWin,
+	})
+	if !almostEqual(p1.elo, 1511.517920) || !almostEqual(p2.elo, 1488.482080) {
+		t.Fail()
+		t.Logf("Expected %f and %f, got %f and %f\n", 1511.517920, 1488.482080, p1.elo, p2.elo)
+	}
+
+	// a negative advantage favors player two
+	m = c.NewMatch(&player{1500}, &player{1500})
+	m.SetAdvantage(-100)
+	if !almostEqual(m.GetAdvantage(), -100) {
+		t.Fail()
+		t.Log("Failed to override advantage.")
+	}
+	o = m.GetOdds()
+	if !almostEqual(o.PlayerTwoOdds, 0.640065) {
+		t.Fail()
+		t.Logf("Expected P2 odds %f, got %f\n", 0.640065, o.PlayerTwoOdds)
+	}
+
+	n1, n2 := c.Calculate(1500, 1500, &elo.MatchResult{
+		Outcome: elo.OutcomePlayerTwoWin,
+	})
+	if !almostEqual(n1, 1479.517920) || !almostEqual(n2, 1520.482080) {
+		t.Fail()
+		t.Logf("Expected %f and %f, got %f and %f\n", 1479.517920, 1520.482080, n1, n2)
+	}
+
+	tm := c.NewTeamMatch([]elo.Player{&player{1500}}, []elo.Player{&player{1500}})
+	if !almostEqual(tm.GetOdds().PlayerOneOdds, 0.640065) {
+		t.Fail()
+		t.Logf("Expected team one odds %f, got %f\n", 0.640065, tm.GetOdds().PlayerOneOdds)
+	}
+}
+
+func TestAdvantageGlicko(t *testing.T) {
+	for _, sf := range []elo.StrategyFunc{elo.StrategyGlicko, elo.StrategyGlicko2} {
+		// an advantage of 100 rates the same as player one being 100 higher
+		a1, a2 := &glickoPlayer{player{1500}, 200, 0.06}, &glickoPlayer{player{1500}, 100, 0.06}
+		elo.NewCalculatorBuilder().WithStrategy(sf).WithAdvantage(100).Build().
+			NewMatch(a1, a2).Play(&elo.MatchResult{Outcome: elo.OutcomePlayerOneWin})
+		b1, b2 := &glickoPlayer{player{1600}, 200, 0.06}, &glickoPlayer{player{1500}, 100, 0.06}
+		elo.NewCalculatorBuilder().WithStrategy(sf).Build().
+			NewMatch(b1, b2).Play(&elo.MatchResult{Outcome: elo.OutcomePlayerOneWin})
+
+		if !almostEqual(a1.elo+100, b1.elo) || !almostEqual(a2.elo, b2.elo) || !almostEqual(a1.rd, b1.rd) {
+			t.Fail()
+			t.Logf("Expected %f and %f, got %f and %f\n", b1.elo-100, b2.elo, a1.elo, a2.elo)
+		}
+	}
+}
+
+func TestEstimateAdvantage(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+
+	// player one scored 75% against equally rated opponents
+	samples := []elo.AdvantageSample{
+		{PlayerOne: 1500, PlayerTwo: 1500, Outcome: elo.OutcomePlayerOneWin},
+		{PlayerOne: 1600, PlayerTwo: 1600, Outcome: elo.OutcomePlayerOneWin},
+		{PlayerOne: 1400, PlayerTwo: 1400, Outcome: elo.OutcomeDraw},
+		{PlayerOne: 1700, PlayerTwo: 1700, Outcome: elo.OutcomeDraw},
+	}
+	expected := 400 * math.Log10(3)
+	if a := c.EstimateAdvantage(samples); !almostEqual(a, expected) {
+		t.Fail()
+		t.Logf("Expected advantage %f, got %f\n", expected, a)
+	}
+
+	// the estimate accounts for the difference in ratings
+	samples = []elo.AdvantageSample{
+		{PlayerOne: 1400, PlayerTwo: 1500, Outcome: elo.OutcomePlayerOneWin},
+		{PlayerOne: 1400, PlayerTwo: 1500, Outcome: elo.OutcomePlayerTwoWin},
+	}
+	if a := c.EstimateAdvantage(samples); !almostEqual(a, 100) {
+		t.Fail()
+		t.Logf("Expected advantage %f, got %f\n", 100.0, a)
+	}
+
+	samples = []elo.AdvantageSample{
+		{PlayerOne: 1500, PlayerTwo: 1500, Outcome: elo.OutcomePlayerTwoWin},
+	}
+	if a := c.EstimateAdvantage(samples); !almostEqual(a, -800) {
+		t.Fail()
+		t.Logf("Expected advantage %f, got %f\n", -800.0, a)
+	}
+	if a := c.EstimateAdvantage(nil); a != 0 {
+		t.Fail()
+		t.Logf("Expected advantage %f, got %f\n", 0.0, a)
+	}
+}
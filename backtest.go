@@ -0,0 +1,143 @@
+package elo
+
+import (
+	"math"
+)
+
+// Number of calibration buckets in a BacktestReport.
+const calibrationBuckets = 10
+
+// A match from a chronological log of matches, between players identified by name.
+type HistoricalMatch struct {
+	PlayerOne string
+	PlayerTwo string
+	Result    MatchResult
+}
+
+// Matches whose predicted expected score for player one fell in the range [Min, Max).
+type CalibrationBucket struct {
+	Min     float64
+	Max     float64
+	Matches int
+
+	// The average expected score of player one predicted before each match.
+	Predicted float64
+
+	// The average score player one actually got.
+	Observed float64
+}
+
+// How well a calculator predicted a log of matches. Every metric compares player one's
+// expected score before each match, which counts half of the odds of a draw, with the
+// score they got: 1 for a win, 0.5 for a draw and 0 for a loss.
+type BacktestReport struct {
+	Matches int
+
+	// The average negative log likelihood of the results. Lower is better, and always
+	// predicting even odds scores ln(2), about 0.693.
+	LogLoss float64
+
+	// The average squared difference between expected and actual score. Lower is better,
+	// and always predicting even odds scores 0.25.
+	BrierScore float64
+
+	// The share of matches won by the player that was favored. Draws, and matches where
+	// neither player was favored, count as half.
+	Accuracy float64
+
+	// Predictions grouped into equal ranges of player one's expected score. A well
+	// calibrated calculator has Observed close to Predicted in every bucket.
+	Calibration []CalibrationBucket
+}
+
+// Replays the log of matches in order, predicting each match with GetOdds before
+// playing it. Every player starts at the given elo, and is kept as a PlayerRecord, so
+// K-Factor functions and Glicko strategies work as they would in a store. Predictions
+// always come from GetOdds, so under Glicko strategies they ignore rating deviation.
+//
+// A result with a winner by score is measured and rated as that player's win, whatever
+// its Outcome, and any other result by its Outcome.
+func (c *Calculator) Backtest(matches []HistoricalMatch, initial float64) *BacktestReport {
+	report := &BacktestReport{
+		Calibration: make([]CalibrationBucket, calibrationBuckets),
+	}
+	for i := range report.Calibration {
+		report.Calibration[i].Min = float64(i) / calibrationBuckets
+		report.Calibration[i].Max = float64(i+1) / calibrationBuckets
+	}
+
+	store := NewMemoryStore()
+	for _, hm := range matches {
+		if hm.PlayerOne == hm.PlayerTwo {
+			continue
+		}
+		for _, id := range []string{hm.PlayerOne, hm.PlayerTwo} {
+			if _, err := store.Get(id); err != nil {
+				store.Put(PlayerRecord{ID: id, Elo: initial, PeakElo: initial})
+			}
+		}
+		p1, _ := store.Get(hm.PlayerOne)
+		p2, _ := store.Get(hm.PlayerTwo)
+		odds := c.NewMatch(&p1, &p2).GetOdds()
+		E := odds.PlayerOneOdds + odds.DrawOdds/2
+		result := scoredResult(&hm.Result)
+		S := resultScore(result)
+
+		report.Matches++
+		clamped := math.Min(math.Max(E, 1e-15), 1-1e-15)
+		report.LogLoss -= S*math.Log(clamped) + (1-S)*math.Log(1-clamped)
+		report.BrierScore += (E - S) * (E - S)
+		if E == 0.5 || S == 0.5 {
+			report.Accuracy += 0.5
+		} else if (E > 0.5) == (S == 1) {
+			report.Accuracy++
+		}
+		b := &report.Calibration[int(math.Min(E*calibrationBuckets, calibrationBuckets-1))]
+		b.Matches++
+		b.Predicted += E
+		b.Observed += S
+
+		c.PlayByID(store, hm.PlayerOne, hm.PlayerTwo, result)
+	}
+
+	if report.Matches > 0 {
+		n := float64(report.Matches)
+		report.LogLoss /= n
+		report.BrierScore /= n
+		report.Accuracy /= n
+	}
+	for i := range report.Calibration {
+		b := &report.Calibration[i]
+		if b.Matches > 0 {
+			b.Predicted /= float64(b.Matches)
+			b.Observed /= float64(b.Matches)
+		}
+	}
+	return report
+}
+
+// Returns player one's score for the result, from the final score if it has a winner
+// and from the outcome otherwise.
+func resultScore(result *MatchResult) float64 {
+	if result.PlayerOneScore > result.PlayerTwoScore {
+		return 1
+	} else if result.PlayerOneScore < result.PlayerTwoScore {
+		return 0
+	}
+	S1, _ := outcomeScores(result.Outcome)
+	return S1
+}
+
+// Returns a copy of the result with the outcome set to agree with resultScore.
+func scoredResult(result *MatchResult) *MatchResult {
+	r := *result
+	switch resultScore(result) {
+	case 1:
+		r.Outcome = OutcomePlayerOneWin
+	case 0:
+		r.Outcome = OutcomePlayerTwoWin
+	default:
+		r.Outcome = OutcomeDraw
+	}
+	return &r
+}
@@ -0,0 +1,78 @@
+package elo_test
+
+import (
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+func TestBacktest(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+
+	log := []elo.HistoricalMatch{
+		{PlayerOne: "a", PlayerTwo: "b", Result: elo.MatchResult{Outcome: elo.OutcomePlayerOneWin}},
+		{PlayerOne: "a", PlayerTwo: "b", Result: elo.MatchResult{Outcome: elo.OutcomePlayerOneWin}},
+		{PlayerOne: "a", PlayerTwo: "b", Result: elo.MatchResult{PlayerOneScore: 1, PlayerTwoScore: 3}},
+	}
+	r := c.Backtest(log, 1500)
+
+	if r.Matches != 3 {
+		t.Fail()
+		t.Logf("Expected 3 matches, got %d\n", r.Matches)
+	}
+	if !almostEqual(r.LogLoss, 0.727562) {
+		t.Fail()
+		t.Logf("Expected log loss %f, got %f\n", 0.727562, r.LogLoss)
+	}
+	if !almostEqual(r.BrierScore, 0.266911) {
+		t.Fail()
+		t.Logf("Expected Brier score %f, got %f\n", 0.266911, r.BrierScore)
+	}
+	// the first match was even, the second was won by the favorite and the third was not
+	if !almostEqual(r.Accuracy, 0.5) {
+		t.Fail()
+		t.Logf("Expected accuracy %f, got %f\n", 0.5, r.Accuracy)
+	}
+
+	if len(r.Calibration) != 10 {
+		t.Fatalf("Expected 10 calibration buckets, got %d\n", len(r.Calibration))
+	}
+	b := r.Calibration[5]
+	if b.Matches != 3 || !almostEqual(b.Predicted, 0.544301) || !almostEqual(b.Observed, 2.0/3) {
+		t.Fail()
+		t.Logf("Expected 3 matches predicted at %f and observed at %f, got %+v\n", 0.544301, 2.0/3, b)
+	}
+	if !almostEqual(b.Min, 0.5) || !almostEqual(b.Max, 0.6) {
+		t.Fail()
+		t.Logf("Expected bucket range [0.5, 0.6), got [%f, %f)\n", b.Min, b.Max)
+	}
+}
+
+func TestBacktestEmpty(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	r := c.Backtest(nil, 1500)
+	if r.Matches != 0 || r.LogLoss != 0 {
+		t.Fail()
+		t.Logf("Expected an empty report, got %+v\n", r)
+	}
+}
+
+func TestBacktestScoredOutcome(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+
+	// a loss by score is rated as a loss even though its outcome is a draw
+	scored := []elo.HistoricalMatch{
+		{PlayerOne: "a", PlayerTwo: "b", Result: elo.MatchResult{PlayerOneScore: 1, PlayerTwoScore: 3, Outcome: elo.OutcomeDraw}},
+		{PlayerOne: "a", PlayerTwo: "b", Result: elo.MatchResult{Outcome: elo.OutcomePlayerOneWin}},
+	}
+	outcome := []elo.HistoricalMatch{
+		{PlayerOne: "a", PlayerTwo: "b", Result: elo.MatchResult{Outcome: elo.OutcomePlayerTwoWin}},
+		{PlayerOne: "a", PlayerTwo: "b", Result: elo.MatchResult{Outcome: elo.OutcomePlayerOneWin}},
+	}
+	r1, r2 := c.Backtest(scored, 1500), c.Backtest(outcome, 1500)
+	if !almostEqual(r1.LogLoss, r2.LogLoss) || !almostEqual(r1.BrierScore, r2.BrierScore) {
+		t.Fail()
+		t.Logf("Expected log loss %f and Brier score %f, got %f and %f\n", r2.LogLoss, r2.BrierScore,
+			r1.LogLoss, r1.BrierScore)
+	}
+}
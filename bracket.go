@@ -0,0 +1,460 @@
+package elo
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+)
+
+var (
+	ErrMatchNotReady = errors.New("elo: the bracket match is not ready to be played")
+	ErrBracketDraw   = errors.New("elo: elimination matches cannot end in a draw")
+)
+
+// Takes the place of a player in a BracketMatch when a player is given a bye.
+const BracketBye = -2
+
+// The part of a bracket a match belongs to.
+type BracketSection int
+
+const (
+	SectionWinners    = BracketSection(0)
+	SectionLosers     = BracketSection(1)
+	SectionGrandFinal = BracketSection(2)
+)
+
+// A match in a Bracket, between players identified by their index in Bracket.Players.
+type BracketMatch struct {
+	ID      int
+	Section BracketSection
+
+	// Rounds are numbered from 1 within each section. In the grand final, round 2 is
+	// the reset, which is only played if the player from the losers bracket wins round 1.
+	Round int
+
+	// The players of the match, -1 until they are known, or BracketBye.
+	PlayerOne int
+	PlayerTwo int
+
+	// -1 until the match is decided.
+	Winner int
+	Loser  int
+
+	// Nil unless the match was played. Byes and skipped resets have no result.
+	Result *MatchResult
+}
+
+// Where a player in a bracket match comes from.
+type bracketSource struct {
+	kind int
+	ref  int
+}
+
+const (
+	sourceSeed = iota
+	sourceWinner
+	sourceLoser
+)
+
+type bracketNode struct {
+	section BracketSection
+	round   int
+	sources [2]bracketSource
+
+	// Matches in later stages are played later, and players eliminated in the same stage
+	// share a place.
+	stage int
+
+	// Whether losing the match eliminates the player. In the grand final, only the
+	// player from the losers bracket is eliminated by a loss.
+	eliminates bool
+
+	// Whether the match is a grand final reset of the match before it.
+	reset bool
+}
+
+// The players, winner and loser of every match in a bracket.
+type bracketState struct {
+	players [][2]int
+	winner  []int
+	loser   []int
+}
+
+func (st *bracketState) copy() *bracketState {
+	return &bracketState{
+		players: append([][2]int(nil), st.players...),
+		winner:  append([]int(nil), st.winner...),
+		loser:   append([]int(nil), st.loser...),
+	}
+}
+
+// A single or double elimination bracket. Players are seeded by their rating when the
+// bracket is created, using the standard pattern where the top seeds can only meet in
+// the latest rounds, and the top seeds are given any byes. In a double elimination
+// bracket, players who lose in the winners bracket drop into the losers bracket, and
+// are eliminated by a second loss. The winner of the losers bracket meets the winner
+// of the winners bracket in the grand final, which is reset and played again if the
+// player from the losers bracket wins it.
+//
+// Bracket implements SimulatedTournament, and simulations start from the results
+// played so far.
+type Bracket struct {
+	Players []Player
+
+	c      Calculator
+	seeds  []int
+	nodes  []bracketNode
+	state  *bracketState
+	result []*MatchResult
+	double bool
+}
+
+// Args players should be non-nil pointers.
+func (c *Calculator) NewSingleElimination(players ...Player) *Bracket {
+	b := c.newBracket(players, false)
+	rounds := b.winnersBracket()
+	for r, round := range rounds {
+		for _, id := range round {
+			b.nodes[id].stage = r
+			b.nodes[id].eliminates = true
+		}
+	}
+	b.resolve(b.state)
+	return b
+}
+
+// Args players should be non-nil pointers.
+func (c *Calculator) NewDoubleElimination(players ...Player) *Bracket {
+	b := c.newBracket(players, true)
+	w := b.winnersBracket()
+	k := len(w)
+
+	// the losers bracket alternates between rounds where players who dropped from the
+	// winners bracket join, and rounds where the remaining players play each other
+	var losers []int
+	lround := 0
+	add := func(s1, s2 bracketSource) int {
+		return b.addNode(bracketNode{
+			section:    SectionLosers,
+			round:      lround,
+			sources:    [2]bracketSource{s1, s2},
+			stage:      lround - 1,
+			eliminates: true,
+		})
+	}
+	if k >= 2 {
+		lround++
+		for i := 0; i < len(w[0])/2; i++ {
+			losers = append(losers, add(bracketSource{sourceLoser, w[0][2*i]}, bracketSource{sourceLoser, w[0][2*i+1]}))
+		}
+	}
+	for r := 1; r < k; r++ {
+		lround++
+		dropIn := make([]int, len(w[r]))
+		for i := range w[r] {
+			// alternate the order players drop in to delay rematches
+			from := w[r][i]
+			if r%2 == 1 {
+				from = w[r][len(w[r])-1-i]
+			}
+			dropIn[i] = add(bracketSource{sourceWinner, losers[i]}, bracketSource{sourceLoser, from})
+		}
+		losers = dropIn
+		if r == k-1 {
+			break
+		}
+		lround++
+		var next []int
+		for i := 0; i < len(losers)/2; i++ {
+			next = append(next, add(bracketSource{sourceWinner, losers[2*i]}, bracketSource{sourceWinner, losers[2*i+1]}))
+		}
+		losers = next
+	}
+
+	champion := bracketSource{sourceWinner, w[k-1][0]}
+	challenger := bracketSource{sourceLoser, w[k-1][0]}
+	if len(losers) > 0 {
+		challenger = bracketSource{sourceWinner, losers[0]}
+	}
+	final := b.addNode(bracketNode{
+		section:    SectionGrandFinal,
+		round:      1,
+		sources:    [2]bracketSource{champion, challenger},
+		stage:      lround,
+		eliminates: true,
+	})
+	b.addNode(bracketNode{
+		section:    SectionGrandFinal,
+		round:      2,
+		sources:    [2]bracketSource{{sourceWinner, final}, {sourceLoser, final}},
+		stage:      lround + 1,
+		eliminates: true,
+		reset:      true,
+	})
+	b.resolve(b.state)
+	return b
+}
+
+func (c *Calculator) newBracket(players []Player, double bool) *Bracket {
+	b := &Bracket{
+		Players: players,
+		c:       *c,
+		seeds:   make([]int, len(players)),
+		state:   new(bracketState),
+		double:  double,
+	}
+	for i := range b.seeds {
+		b.seeds[i] = i
+	}
+	ratings := make([]float64, len(players))
+	for i, p := range players {
+		ratings[i] = p.GetElo()
+	}
+	sort.SliceStable(b.seeds, func(i, j int) bool {
+		return ratings[b.seeds[i]] > ratings[b.seeds[j]]
+	})
+	return b
+}
+
+// Adds the rounds of the winners bracket, returning the matches of each round.
+func (b *Bracket) winnersBracket() [][]int {
+	var rounds [][]int
+	var round []int
+	order := standardSeeding(len(b.Players))
+	for i := 0; i < len(order); i += 2 {
+		round = append(round, b.addNode(bracketNode{
+			section: SectionWinners,
+			round:   1,
+			sources: [2]bracketSource{{sourceSeed, order[i]}, {sourceSeed, order[i+1]}},
+		}))
+	}
+	rounds = append(rounds, round)
+	for len(round) > 1 {
+		var next []int
+		for i := 0; i < len(round); i += 2 {
+			next = append(next, b.addNode(bracketNode{
+				section: SectionWinners,
+				round:   len(rounds) + 1,
+				sources: [2]bracketSource{{sourceWinner, round[i]}, {sourceWinner, round[i+1]}},
+			}))
+		}
+		round = next
+		rounds = append(rounds, round)
+	}
+	return rounds
+}
+
+func (b *Bracket) addNode(n bracketNode) int {
+	b.nodes = append(b.nodes, n)
+	b.state.players = append(b.state.players, [2]int{-1, -1})
+	b.state.winner = append(b.state.winner, -1)
+	b.state.loser = append(b.state.loser, -1)
+	b.result = append(b.result, nil)
+	return len(b.nodes) - 1
+}
+
+// Returns the seeds, numbered from 0, in the order of the first round's slots, for the
+// smallest bracket that fits n players. Seeds of n or more are byes.
+func standardSeeding(n int) []int {
+	order := []int{0, 1}
+	for len(order) < n {
+		size := len(order) * 2
+		next := make([]int, 0, size)
+		for _, s := range order {
+			next = append(next, s, size-1-s)
+		}
+		order = next
+	}
+	return order
+}
+
+// Fills in every match's players from the matches before it, and decides matches
+// against a bye. Matches are stored in the order they are played, so a single pass
+// is enough.
+func (b *Bracket) resolve(st *bracketState) {
+	for i := range b.nodes {
+		b.resolveNode(i, st)
+	}
+}
+
+func (b *Bracket) resolveNode(i int, st *bracketState) {
+	if st.winner[i] != -1 {
+		return
+	}
+	n := &b.nodes[i]
+	for s, src := range n.sources {
+		if st.players[i][s] != -1 {
+			continue
+		}
+		switch src.kind {
+		case sourceSeed:
+			st.players[i][s] = BracketBye
+			if src.ref < len(b.seeds) {
+				st.players[i][s] = b.seeds[src.ref]
+			}
+		case sourceWinner:
+			st.players[i][s] = st.winner[src.ref]
+		case sourceLoser:
+			st.players[i][s] = st.loser[src.ref]
+		}
+	}
+	p1, p2 := st.players[i][0], st.players[i][1]
+	if p1 == -1 || p2 == -1 {
+		return
+	}
+	if n.reset && p1 == st.players[i-1][0] {
+		// the winners bracket champion won the grand final, so there is no reset
+		st.players[i] = [2]int{BracketBye, BracketBye}
+		st.winner[i], st.loser[i] = p1, BracketBye
+		return
+	}
+	if p1 == BracketBye {
+		st.winner[i], st.loser[i] = p2, BracketBye
+	} else if p2 == BracketBye {
+		st.winner[i], st.loser[i] = p1, BracketBye
+	}
+}
+
+// Returns every match in the bracket, in the order they can be played.
+func (b *Bracket) Matches() []BracketMatch {
+	matches := make([]BracketMatch, len(b.nodes))
+	for i := range b.nodes {
+		matches[i] = b.match(i)
+	}
+	return matches
+}
+
+// Returns every match whose players are known but has not been played yet.
+func (b *Bracket) Ready() []BracketMatch {
+	var ready []BracketMatch
+	for i := range b.nodes {
+		if b.ready(i) {
+			ready = append(ready, b.match(i))
+		}
+	}
+	return ready
+}
+
+func (b *Bracket) ready(i int) bool {
+	p := b.state.players[i]
+	return b.state.winner[i] == -1 && p[0] >= 0 && p[1] >= 0
+}
+
+func (b *Bracket) match(i int) BracketMatch {
+	return BracketMatch{
+		ID:        i,
+		Section:   b.nodes[i].section,
+		Round:     b.nodes[i].round,
+		PlayerOne: b.state.players[i][0],
+		PlayerTwo: b.state.players[i][1],
+		Winner:    b.state.winner[i],
+		Loser:     b.state.loser[i],
+		Result:    b.result[i],
+	}
+}
+
+// Plays a ready match, updating both players' ratings, and advances the winner and the
+// loser to their next matches. A result with a winner by score is rated as that player's
+// win, whatever its Outcome.
+func (b *Bracket) Play(id int, result *MatchResult) error {
+	if id < 0 || id >= len(b.nodes) || !b.ready(id) {
+		return ErrMatchNotReady
+	}
+	r := scoredResult(result)
+	S1 := resultScore(r)
+	if S1 == 0.5 {
+		return ErrBracketDraw
+	}
+	p1, p2 := b.state.players[id][0], b.state.players[id][1]
+	b.c.NewMatch(b.Players[p1], b.Players[p2]).Play(r)
+
+	b.result[id] = r
+	b.state.winner[id], b.state.loser[id] = p1, p2
+	if S1 == 0 {
+		b.state.winner[id], b.state.loser[id] = p2, p1
+	}
+	b.resolve(b.state)
+	return nil
+}
+
+// Returns the winner of the bracket, or -1 if it has not been decided.
+func (b *Bracket) Champion() int {
+	return max(b.state.winner[len(b.nodes)-1], -1)
+}
+
+// Plays out the rest of the bracket. Places are shared by players eliminated in the same
+// round. For a single elimination bracket, rounds are the rounds of the bracket each
+// player reached, with the champion reaching the round after the final. A double
+// elimination bracket has no rounds.
+func (b *Bracket) Simulate(n int, play func(p1, p2 int) MatchOutcome, rng *rand.Rand) ([]int, []int) {
+	st := b.state.copy()
+	for i := range b.nodes {
+		b.resolveNode(i, st)
+		p1, p2 := st.players[i][0], st.players[i][1]
+		if st.winner[i] != -1 || p1 < 0 || p2 < 0 {
+			continue
+		}
+		outcome := play(p1, p2)
+		for outcome == OutcomeDraw {
+			outcome = play(p1, p2)
+		}
+		st.winner[i], st.loser[i] = p1, p2
+		if outcome == OutcomePlayerTwoWin {
+			st.winner[i], st.loser[i] = p2, p1
+		}
+	}
+	return b.placements(n, st)
+}
+
+// Returns each player's place and, for a single elimination bracket, the rounds they
+// reached, from a bracket that has been played out.
+func (b *Bracket) placements(n int, st *bracketState) ([]int, []int) {
+	places := make([]int, n)
+	for p := range places {
+		places[p] = -1
+	}
+	eliminated := make(map[int]int)
+	stages := make(map[int]int)
+	for i, node := range b.nodes {
+		l := st.loser[i]
+		if l < 0 || !node.eliminates {
+			continue
+		}
+		if node.section == SectionGrandFinal && node.round == 1 && l == st.players[i][0] {
+			// the winners bracket champion lost the grand final, which forces a reset
+			continue
+		}
+		eliminated[l] = node.stage
+		stages[node.stage]++
+	}
+	for p, stage := range eliminated {
+		// players eliminated later finished above the player, as did the champion
+		place := 1
+		for s, count := range stages {
+			if s > stage {
+				place += count
+			}
+		}
+		places[p] = place
+	}
+	if champion := st.winner[len(b.nodes)-1]; champion >= 0 {
+		places[champion] = 0
+	}
+
+	if b.double {
+		return places, nil
+	}
+	rounds := make([]int, n)
+	for p := range rounds {
+		rounds[p] = -1
+	}
+	for _, p := range b.seeds {
+		rounds[p] = 0
+	}
+	for i, node := range b.nodes {
+		if w := st.winner[i]; w >= 0 {
+			rounds[w] = node.round
+		}
+	}
+	return places, rounds
+}
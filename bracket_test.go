@@ -0,0 +1,194 @@
+package elo_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+// Plays every ready match as a win for the player who was higher rated when the bracket
+// was created, until none are left. Returns the number of matches played.
+func playFavorites(t *testing.T, b *elo.Bracket) int {
+	played := 0
+	for ready := b.Ready(); len(ready) > 0; ready = b.Ready() {
+		for _, m := range ready {
+			outcome := elo.OutcomePlayerOneWin
+			if m.PlayerTwo < m.PlayerOne {
+				outcome = elo.OutcomePlayerTwoWin
+			}
+			if err := b.Play(m.ID, &elo.MatchResult{Outcome: outcome}); err != nil {
+				t.Fatal(err)
+			}
+			played++
+		}
+	}
+	return played
+}
+
+func TestSingleElimination(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	b := c.NewSingleElimination(swissPlayers(8)...)
+
+	// 1 v 8, 4 v 5, 2 v 7 and 3 v 6
+	expected := [][2]int{{0, 7}, {3, 4}, {1, 6}, {2, 5}}
+	ready := b.Ready()
+	if len(ready) != 4 {
+		t.Fatalf("Expected 4 ready matches, got %d\n", len(ready))
+	}
+	for i, m := range ready {
+		if m.PlayerOne != expected[i][0] || m.PlayerTwo != expected[i][1] || m.Round != 1 {
+			t.Fail()
+			t.Logf("Expected %v, got %d v %d\n", expected[i], m.PlayerOne, m.PlayerTwo)
+		}
+	}
+	if err := b.Play(ready[0].ID, &elo.MatchResult{Outcome: elo.OutcomeDraw}); err != elo.ErrBracketDraw {
+		t.Fail()
+		t.Logf("Expected ErrBracketDraw, got %v\n", err)
+	}
+	if err := b.Play(len(b.Matches())-1, &elo.MatchResult{}); err != elo.ErrMatchNotReady {
+		t.Fail()
+		t.Logf("Expected ErrMatchNotReady, got %v\n", err)
+	}
+
+	if n := playFavorites(t, b); n != 7 {
+		t.Fail()
+		t.Logf("Expected 7 matches, got %d\n", n)
+	}
+	if b.Champion() != 0 {
+		t.Fail()
+		t.Logf("Expected the top seed to win, got %d\n", b.Champion())
+	}
+	if almostEqual(b.Players[0].GetElo(), 2000) {
+		t.Fail()
+		t.Log("Expected results to be rated")
+	}
+}
+
+func TestBracketScoredResult(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	players := []elo.Player{&player{1500}, &player{1500}}
+	b := c.NewSingleElimination(players...)
+
+	// a win by score advances the winner and is rated as a win
+	m := b.Ready()[0]
+	if err := b.Play(m.ID, &elo.MatchResult{PlayerOneScore: 3, PlayerTwoScore: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if b.Champion() != m.PlayerOne || !almostEqual(players[m.PlayerOne].GetElo(), 1516) ||
+		!almostEqual(players[m.PlayerTwo].GetElo(), 1484) {
+		t.Fail()
+		t.Logf("Expected %d to win with %f, got %d with %f\n", m.PlayerOne, 1516.0, b.Champion(),
+			players[m.PlayerOne].GetElo())
+	}
+}
+
+func TestSingleEliminationByes(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	b := c.NewSingleElimination(swissPlayers(6)...)
+
+	// the top two seeds have byes
+	ready := b.Ready()
+	if len(ready) != 2 || ready[0].PlayerOne != 3 || ready[1].PlayerOne != 2 {
+		t.Fatalf("Unexpected first round %+v\n", ready)
+	}
+	if n := playFavorites(t, b); n != 5 || b.Champion() != 0 {
+		t.Fail()
+		t.Logf("Expected 5 matches won by the top seed, got %d won by %d\n", n, b.Champion())
+	}
+}
+
+func TestDoubleElimination(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	b := c.NewDoubleElimination(swissPlayers(8)...)
+
+	// 7 winners, 6 losers and 2 grand final matches
+	if n := len(b.Matches()); n != 15 {
+		t.Fail()
+		t.Logf("Expected 15 matches, got %d\n", n)
+	}
+	// without upsets the grand final is not reset
+	if n := playFavorites(t, b); n != 14 || b.Champion() != 0 {
+		t.Fail()
+		t.Logf("Expected 14 matches won by the top seed, got %d won by %d\n", n, b.Champion())
+	}
+	for _, m := range b.Matches() {
+		if m.Section == elo.SectionLosers && m.Winner != 1 && m.Round == 4 {
+			t.Fail()
+			t.Logf("Expected the second seed to win the losers bracket, got %d\n", m.Winner)
+		}
+	}
+}
+
+func TestDoubleEliminationReset(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	b := c.NewDoubleElimination(swissPlayers(4)...)
+
+	// the top seed loses the first round, but comes back through the losers bracket
+	first := b.Ready()[0]
+	b.Play(first.ID, &elo.MatchResult{Outcome: elo.OutcomePlayerTwoWin})
+	for len(b.Ready()) > 0 {
+		m := b.Ready()[0]
+		if m.Section == elo.SectionGrandFinal && m.Round == 2 {
+			break
+		}
+		outcome := elo.OutcomePlayerOneWin
+		if m.PlayerTwo == 0 || (m.PlayerOne != 0 && m.PlayerTwo < m.PlayerOne) {
+			outcome = elo.OutcomePlayerTwoWin
+		}
+		b.Play(m.ID, &elo.MatchResult{Outcome: outcome})
+	}
+
+	ready := b.Ready()
+	if len(ready) != 1 || ready[0].Section != elo.SectionGrandFinal || ready[0].Round != 2 || ready[0].PlayerOne != 0 {
+		t.Fatalf("Expected a grand final reset, got %+v\n", ready)
+	}
+	if b.Champion() != -1 {
+		t.Fail()
+		t.Log("Expected no champion before the reset")
+	}
+	b.Play(ready[0].ID, &elo.MatchResult{Outcome: elo.OutcomePlayerOneWin})
+	if b.Champion() != 0 {
+		t.Fail()
+		t.Logf("Expected the top seed to win the reset, got %d\n", b.Champion())
+	}
+}
+
+func TestSimulateBracket(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	players := []elo.Player{&player{1500}, &player{1500}, &player{1500}, &player{1500}}
+
+	for _, b := range []*elo.Bracket{c.NewSingleElimination(players...), c.NewDoubleElimination(players...)} {
+		s := c.NewSimulator(players...)
+		s.SetIterations(20000)
+		r := s.Run(b)
+		for p := range players {
+			if math.Abs(r.WinProbability(p)-0.25) > 0.015 {
+				t.Fail()
+				t.Logf("Expected win probability %f, got %f\n", 0.25, r.WinProbability(p))
+			}
+			var sum float64
+			for _, prob := range r.Places[p] {
+				sum += prob
+			}
+			if !almostEqual(sum, 1) {
+				t.Fail()
+				t.Logf("Expected place probabilities to sum to 1, got %f\n", sum)
+			}
+		}
+	}
+
+	// simulations start from the results played so far
+	b := c.NewSingleElimination(players...)
+	m := b.Ready()[0]
+	b.Play(m.ID, &elo.MatchResult{Outcome: elo.OutcomePlayerTwoWin})
+	r := c.NewSimulator(players...).Run(b)
+	if r.WinProbability(m.PlayerOne) != 0 || !almostEqual(r.Places[m.PlayerOne][2], 1) {
+		t.Fail()
+		t.Logf("Expected an eliminated player to finish third, got %v\n", r.Places[m.PlayerOne])
+	}
+	if !almostEqual(r.Rounds[m.PlayerTwo][1], 1) {
+		t.Fail()
+		t.Logf("Expected the winner to reach the final, got %v\n", r.Rounds[m.PlayerTwo])
+	}
+}
@@ -1,5 +1,9 @@
 package elo
 
+// Default autocorrelation constant for StrategyMarginOfVictory, as used by
+// FiveThirtyEight's NFL model.
+const DefaultAutocorrelation = 2.2
+
 type CalculatorBuilder struct {
 	c Calculator
 }
@@ -9,7 +13,19 @@ type Calculator struct {
 	deviation   float64
 	scoreWeight float64
 	ignoreDraws bool
+	tau         float64
+	glickoC     float64
 	strategy    StrategyFunc
+	kFactor     KFactorFunc
+
+	drawParameter float64
+
+	autocorrelation float64
+
+	advantage float64
+
+	teamAggregator  TeamAggregator
+	teamDistributor TeamDistributor
 }
 
 func NewCalculatorBuilder() *CalculatorBuilder {
@@ -17,7 +33,14 @@ func NewCalculatorBuilder() *CalculatorBuilder {
 		c: Calculator{
 			k:         32,
 			deviation: 400,
+			tau:       DefaultTau,
+			glickoC:   DefaultGlickoC,
 			strategy:  StrategyDefault,
+
+			autocorrelation: DefaultAutocorrelation,
+
+			teamAggregator:  TeamMean,
+			teamDistributor: DistributeEqual,
 		}}
 }
 
@@ -53,6 +76,17 @@ func (b *CalculatorBuilder) WithScoreWeight(w float64) *CalculatorBuilder {
 	return b
 }
 
+// Set the autocorrelation constant used by StrategyMarginOfVictory. The lower the number,
+// the less elo a favorite gains for winning by a large margin. Must be greater than 0.
+// Providing a non-positive value will result in no change. Default is 2.2.
+func (b *CalculatorBuilder) WithAutocorrelation(a float64) *CalculatorBuilder {
+	if a <= 0 {
+		return b
+	}
+	b.c.autocorrelation = a
+	return b
+}
+
 // Set a deviation. The lower the number, the greater the probabilty that
 // the higher-rated player wins (and therefore less elo gained). Default is 400.
 func (b *CalculatorBuilder) WithIgnoreDraws() *CalculatorBuilder {
@@ -74,17 +108,61 @@ func (c *Calculator) Calculate(p1, p2 float64, result *MatchResult) (float64, fl
 		return p1, p2
 	}
 	return c.strategy(&CalculatorInput{
-		PlayerOne:      p1,
-		PlayerTwo:      p2,
-		PlayerOneScore: result.PlayerOneScore,
-		PlayerTwoScore: result.PlayerTwoScore,
-		Outcome:        result.Outcome,
-		K:              c.k,
-		Deviation:      c.deviation,
-		ScoreWeight:    c.scoreWeight,
+		PlayerOne:       p1,
+		PlayerTwo:       p2,
+		PlayerOneScore:  result.PlayerOneScore,
+		PlayerTwoScore:  result.PlayerTwoScore,
+		Outcome:         result.Outcome,
+		K:               c.k,
+		Deviation:       c.deviation,
+		ScoreWeight:     c.scoreWeight,
+		Tau:             c.tau,
+		C:               c.glickoC,
+		DrawParameter:   c.drawParameter,
+		Autocorrelation: c.autocorrelation,
+		Advantage:       c.advantage,
 	})
 }
 
+// Calculate rating changes for players with a rating deviation and volatility using the
+// calculator. Returns player one and player two's new ratings respectively.
+func (c *Calculator) CalculateGlicko(p1, p2 GlickoRating, result *MatchResult) (GlickoRating, GlickoRating) {
+	if (result.Outcome == OutcomeDraw) &&
+		c.ignoreDraws &&
+		(result.PlayerOneScore == result.PlayerTwoScore) {
+		return p1, p2
+	}
+	input := &CalculatorInput{
+		PlayerOne:                p1.Rating,
+		PlayerTwo:                p2.Rating,
+		PlayerOneRatingDeviation: p1.RatingDeviation,
+		PlayerTwoRatingDeviation: p2.RatingDeviation,
+		PlayerOneVolatility:      p1.Volatility,
+		PlayerTwoVolatility:      p2.Volatility,
+		PlayerOneScore:           result.PlayerOneScore,
+		PlayerTwoScore:           result.PlayerTwoScore,
+		Outcome:                  result.Outcome,
+		K:                        c.k,
+		Deviation:                c.deviation,
+		ScoreWeight:              c.scoreWeight,
+		Tau:                      c.tau,
+		C:                        c.glickoC,
+		DrawParameter:            c.drawParameter,
+		Autocorrelation:          c.autocorrelation,
+		Advantage:                c.advantage,
+	}
+	n1, n2 := c.strategy(input)
+	return GlickoRating{
+		Rating:          n1,
+		RatingDeviation: input.PlayerOneRatingDeviation,
+		Volatility:      input.PlayerOneVolatility,
+	}, GlickoRating{
+		Rating:          n2,
+		RatingDeviation: input.PlayerTwoRatingDeviation,
+		Volatility:      input.PlayerTwoVolatility,
+	}
+}
+
 type CalculatorInput struct {
 
 	// Required. Elo of Player 1.
@@ -106,9 +184,55 @@ type CalculatorInput struct {
 	// A greater K value means more rapid changes.
 	K float64
 
+	// Optional. K-Value for Player 1 only. Overrides K when greater than 0.
+	PlayerOneK float64
+
+	// Optional. K-Value for Player 2 only. Overrides K when greater than 0.
+	PlayerTwoK float64
+
 	// Required. Deviation, as provided by the Calculator.
 	Deviation float64
 
 	// Required for scored strategies.
 	ScoreWeight float64
+
+	// Used by Glicko strategies. Rating deviation of Player 1.
+	// Updated in place by the strategy.
+	PlayerOneRatingDeviation float64
+
+	// Used by Glicko strategies. Rating deviation of Player 2.
+	// Updated in place by the strategy.
+	PlayerTwoRatingDeviation float64
+
+	// Used by StrategyGlicko2. Volatility of Player 1.
+	// Updated in place by the strategy.
+	PlayerOneVolatility float64
+
+	// Used by StrategyGlicko2. Volatility of Player 2.
+	// Updated in place by the strategy.
+	PlayerTwoVolatility float64
+
+	// Used by StrategyGlicko2. Constrains the change in volatility over time.
+	Tau float64
+
+	// Used by Glicko strategies. Rating periods since Player 1 last played.
+	PlayerOneIdlePeriods float64
+
+	// Used by Glicko strategies. Rating periods since Player 2 last played.
+	PlayerTwoIdlePeriods float64
+
+	// Used by StrategyGlicko. Determines how quickly rating deviation grows
+	// while a player is inactive.
+	C float64
+
+	// Used by elo strategies. Determines how likely draws are.
+	DrawParameter float64
+
+	// Used by StrategyMarginOfVictory. Reduces the elo a favorite gains
+	// for a large margin of victory.
+	Autocorrelation float64
+
+	// Used by elo and Glicko strategies. Added to Player 1's elo when calculating
+	// expectations, to account for an advantage such as home field or moving first.
+	Advantage float64
 }
@@ -0,0 +1,56 @@
+package elo
+
+import (
+	"math"
+)
+
+// Set the Davidson draw parameter, which determines how likely draws are. With a
+// draw parameter, GetOdds also returns the odds of a draw, and every elo strategy uses
+// the same odds for each player's expected score, counting a draw as half a win, so the
+// ratings applied agree with the odds reported. Must be non-negative. Providing a
+// negative value will result in no change. Default is 0, which means draws are not
+// modelled.
+func (b *CalculatorBuilder) WithDrawParameter(nu float64) *CalculatorBuilder {
+	if nu < 0 {
+		return b
+	}
+	b.c.drawParameter = nu
+	return b
+}
+
+// Returns the Davidson draw parameter under which two evenly matched players draw
+// with the given probability. p must be at least 0 and less than 1. Returns 0, which
+// means draws are not modelled, for any other value.
+func DrawParameterFromRate(p float64) float64 {
+	if p < 0 || p >= 1 || math.IsNaN(p) {
+		return 0
+	}
+	return 2 * p / (1 - p)
+}
+
+// Calculates elo using the Elo-Davidson model, where the expected score of each player
+// accounts for the probability of a draw. Every elo strategy expects scores this
+// way, so StrategyDavidson is StrategyDefault, and with a draw parameter of 0 both match
+// the standard elo calculation.
+var StrategyDavidson = StrategyDefault
+
+// Returns each player's expected score under the Davidson model, including any advantage,
+// where a draw counts as half a win.
+func expectedScores(input *CalculatorInput) (E1, E2 float64) {
+	odds := davidsonOdds(input.PlayerOne+input.Advantage, input.PlayerTwo, input.Deviation, input.DrawParameter)
+	return odds.PlayerOneOdds + odds.DrawOdds/2, odds.PlayerTwoOdds + odds.DrawOdds/2
+}
+
+// Returns the odds of each player winning and of a draw under the Davidson model.
+// With a draw parameter of 0, the odds are the same as the standard elo expectation.
+func davidsonOdds(p1, p2, deviation, nu float64) *MatchOdds {
+	R1 := math.Pow(10, p1/deviation)
+	R2 := math.Pow(10, p2/deviation)
+	D := nu * math.Sqrt(R1*R2)
+
+	return &MatchOdds{
+		PlayerOneOdds: R1 / (R1 + R2 + D),
+		PlayerTwoOdds: R2 / (R1 + R2 + D),
+		DrawOdds:      D / (R1 + R2 + D),
+	}
+}
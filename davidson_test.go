@@ -0,0 +1,114 @@
+package elo_test
+
+import (
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+func TestDavidsonOdds(t *testing.T) {
+	c := elo.NewCalculatorBuilder().
+		WithDrawParameter(elo.DrawParameterFromRate(1.0 / 3)).
+		WithDrawParameter(-1). // will be ignored
+		Build()
+
+	p1 := &player{1500}
+	p2 := &player{1500}
+
+	o := c.NewMatch(p1, p2).GetOdds()
+	if !almostEqual(o.PlayerOneOdds, 1.0/3) || !almostEqual(o.PlayerTwoOdds, 1.0/3) || !almostEqual(o.DrawOdds, 1.0/3) {
+		t.Fail()
+		t.Logf("Expected even odds, got %+v\n", o)
+	}
+
+	p2.elo = 1700
+	o = c.NewMatch(p1, p2).GetOdds()
+	if !almostEqual(o.PlayerOneOdds+o.PlayerTwoOdds+o.DrawOdds, 1) {
+		t.Fail()
+		t.Logf("Odds must sum to 1, got %+v\n", o)
+	}
+	if !almostEqual(o.PlayerOneOdds, 0.168334) || !almostEqual(o.DrawOdds, 0.299346) {
+		t.Fail()
+		t.Logf("Expected P1 odds %f and draw odds %f, got %f and %f\n", 0.168334, 0.299346, o.PlayerOneOdds, o.DrawOdds)
+	}
+
+	m := c.NewMatch(p1, p2)
+	m.SetDrawParameter(0)
+	m.SetDrawParameter(-1) // will be ignored
+	if !almostEqual(m.GetDrawParameter(), 0) {
+		t.Fail()
+		t.Log("Failed to override draw parameter.")
+	}
+	o = m.GetOdds()
+	if !almostEqual(o.PlayerOneOdds, 0.240253) || o.DrawOdds != 0 {
+		t.Fail()
+		t.Logf("Without a draw parameter odds must match the default, got %+v\n", o)
+	}
+}
+
+func TestDavidsonStrategy(t *testing.T) {
+	// without a draw parameter, results match StrategyDefault
+	c := elo.NewCalculatorBuilder().
+		WithStrategy(elo.StrategyDavidson).
+		Build()
+
+	n1, n2 := c.Calculate(1600, 1800, &elo.MatchResult{
+		Outcome: elo.OutcomeDraw,
+	})
+	if !almostEqual(n1, 1608.311902) || !almostEqual(n2, 1791.688098) {
+		t.Fail()
+		t.Logf("Expected %f and %f, got %f and %f\n", 1608.311902, 1791.688098, n1, n2)
+	}
+
+	c = elo.NewCalculatorBuilder().
+		WithStrategy(elo.StrategyDavidson).
+		WithDrawParameter(1).
+		Build()
+
+	p1 := &player{1600}
+	p2 := &player{1800}
+	m := c.NewMatch(p1, p2)
+	m.Play(&elo.MatchResult{
+		Outcome: elo.OutcomeDraw,
+	})
+
+	if !almostEqual(p1.elo-1600, 1800-p2.elo) {
+		t.Fail()
+		t.Log("Elo gained and lost must be equal")
+	}
+	if !almostEqual(p1.elo, 1605.823771) {
+		t.Fail()
+		t.Logf("Expected P1 Elo %f, got %f\n", 1605.823771, p1.elo)
+	}
+
+	// equal players drawing do not change
+	n1, n2 = c.Calculate(1500, 1500, &elo.MatchResult{
+		Outcome: elo.OutcomeDraw,
+	})
+	if !almostEqual(n1, 1500) || !almostEqual(n2, 1500) {
+		t.Fail()
+		t.Logf("Expected no change, got %f and %f\n", n1, n2)
+	}
+}
+
+func TestDrawParameterConsistency(t *testing.T) {
+	// every elo strategy expects the scores given by the odds
+	for _, strategy := range []elo.StrategyFunc{elo.StrategyDefault, elo.StrategyScored} {
+		c := elo.NewCalculatorBuilder().
+			WithStrategy(strategy).
+			WithDrawParameter(1).
+			Build()
+		n1, _ := c.Calculate(1600, 1800, &elo.MatchResult{Outcome: elo.OutcomeDraw, PlayerOneScore: 1, PlayerTwoScore: 1})
+		if !almostEqual(n1, 1605.823771) {
+			t.Fail()
+			t.Logf("Expected P1 Elo %f, got %f\n", 1605.823771, n1)
+		}
+	}
+
+	for _, p := range []float64{-0.1, 1, 2} {
+		if nu := elo.DrawParameterFromRate(p); nu != 0 {
+			t.Fail()
+			t.Logf("Expected a draw parameter of 0 for %f, got %f\n", p, nu)
+		}
+	}
+}
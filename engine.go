@@ -0,0 +1,133 @@
+package elo
+
+import (
+	"sort"
+	"sync"
+)
+
+// Plays matches from many goroutines at once. Matches that share a player are played
+// one after the other, so no match reads a rating that another is about to change,
+// while matches between different players are played in parallel.
+//
+// Each player is given a lock while the engine is playing their matches, and locks are
+// always taken in the order they were given, so matches can never deadlock. A player's
+// lock is dropped once none of their matches are being played, so the engine does not
+// keep players it is done with. Players are told apart by their identity, so they should
+// be pointers, and every access to a player's rating while matches are being played
+// should go through the same engine.
+type Engine struct {
+	mu    sync.Mutex
+	locks map[Player]*engineLock
+	next  uint64
+}
+
+type engineLock struct {
+	sync.Mutex
+	order uint64
+
+	// The number of calls holding or waiting for the lock. Guarded by Engine.mu.
+	users int
+}
+
+func NewEngine() *Engine {
+	return &Engine{
+		locks: make(map[Player]*engineLock),
+	}
+}
+
+// Runs fn while holding the lock of every given player. Players may be repeated.
+func (e *Engine) Do(players []Player, fn func()) {
+	locks := e.lockers(players)
+	for _, l := range locks {
+		l.Lock()
+	}
+	defer func() {
+		for i := len(locks) - 1; i >= 0; i-- {
+			locks[i].Unlock()
+		}
+		e.release(players, locks)
+	}()
+	fn()
+}
+
+// Plays the match once no other match involving either player is being played.
+func (e *Engine) Play(m *Match, result *MatchResult) {
+	e.Do([]Player{m.PlayerOne, m.PlayerTwo}, func() {
+		m.Play(result)
+	})
+}
+
+// Plays the team match once no other match involving any of its players is being played.
+func (e *Engine) PlayTeamMatch(m *TeamMatch, result *MatchResult) {
+	players := append(append([]Player{}, m.TeamOne...), m.TeamTwo...)
+	e.Do(players, func() {
+		m.Play(result)
+	})
+}
+
+// Plays the free-for-all match once no other match involving any of its players is
+// being played.
+func (e *Engine) PlayMultiMatch(m *MultiMatch, placements []int) error {
+	var err error
+	e.Do(m.Players, func() {
+		err = m.Play(placements)
+	})
+	return err
+}
+
+// Returns the odds of the match, reading both ratings under their locks.
+func (e *Engine) GetOdds(m *Match) *MatchOdds {
+	var odds *MatchOdds
+	e.Do([]Player{m.PlayerOne, m.PlayerTwo}, func() {
+		odds = m.GetOdds()
+	})
+	return odds
+}
+
+// Returns the number of players whose matches are being played or are waiting to be.
+func (e *Engine) Len() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.locks)
+}
+
+// Returns the locks of the given players without duplicates, in the order they must
+// be taken.
+func (e *Engine) lockers(players []Player) []*engineLock {
+	e.mu.Lock()
+	locks := make([]*engineLock, 0, len(players))
+	seen := make(map[*engineLock]bool, len(players))
+	for _, p := range players {
+		l, ok := e.locks[p]
+		if !ok {
+			l = &engineLock{order: e.next}
+			e.next++
+			e.locks[p] = l
+		}
+		if !seen[l] {
+			seen[l] = true
+			l.users++
+			locks = append(locks, l)
+		}
+	}
+	e.mu.Unlock()
+
+	sort.Slice(locks, func(i, j int) bool {
+		return locks[i].order < locks[j].order
+	})
+	return locks
+}
+
+// Drops the locks taken by lockers that no other call is holding or waiting for.
+func (e *Engine) release(players []Player, locks []*engineLock) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, l := range locks {
+		l.users--
+	}
+	for _, p := range players {
+		if l, ok := e.locks[p]; ok && l.users == 0 {
+			delete(e.locks, p)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package elo_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+func TestEngine(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	e := elo.NewEngine()
+
+	players := make([]elo.Player, 8)
+	for i := range players {
+		players[i] = &player{1500}
+	}
+
+	// every match is zero-sum, so a lost update would change the total
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				// play pairs in both orders to check that locking cannot deadlock
+				p1 := players[(g+i)%len(players)]
+				p2 := players[(g*3+i*5+1)%len(players)]
+				if p1 == p2 {
+					continue
+				}
+				outcome := elo.OutcomePlayerOneWin
+				if (g+i)%3 == 0 {
+					outcome = elo.OutcomePlayerTwoWin
+				}
+				m := c.NewMatch(p1, p2)
+				e.Play(m, &elo.MatchResult{Outcome: outcome})
+				e.GetOdds(c.NewMatch(p2, p1))
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	var total float64
+	for _, p := range players {
+		total += p.GetElo()
+	}
+	if !almostEqual(total, 1500*float64(len(players))) {
+		t.Fail()
+		t.Logf("Expected total elo %f, got %f\n", 1500*float64(len(players)), total)
+	}
+}
+
+func TestEngineTeamAndMultiMatch(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	e := elo.NewEngine()
+
+	players := []elo.Player{&player{1500}, &player{1500}, &player{1500}, &player{1500}}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if g%2 == 0 {
+					m := c.NewTeamMatch(players[:2], players[2:])
+					e.PlayTeamMatch(m, &elo.MatchResult{Outcome: elo.OutcomePlayerOneWin})
+				} else {
+					m := c.NewMultiMatch(players[3], players[1], players[0])
+					if err := e.PlayMultiMatch(m, []int{1, 2, 3}); err != nil {
+						t.Error(err)
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	var total float64
+	for _, p := range players {
+		total += p.GetElo()
+	}
+	if !almostEqual(total, 6000) {
+		t.Fail()
+		t.Logf("Expected total elo %f, got %f\n", 6000.0, total)
+	}
+}
+
+func TestEngineDo(t *testing.T) {
+	e := elo.NewEngine()
+	p := &player{0}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				// repeated players are only locked once
+				e.Do([]elo.Player{p, p}, func() {
+					p.SetElo(p.GetElo() + 1)
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !almostEqual(p.elo, 800) {
+		t.Fail()
+		t.Logf("Expected %f, got %f\n", 800.0, p.elo)
+	}
+	// players are dropped once their matches are done
+	if e.Len() != 0 {
+		t.Fail()
+		t.Logf("Expected no players to be kept, got %d\n", e.Len())
+	}
+}
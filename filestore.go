@@ -0,0 +1,195 @@
+package elo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var ErrCorruptStore = errors.New("elo: store file is corrupt")
+
+// A PlayerStore that keeps players in memory and appends every change to a JSON-lines
+// file. Each line holds a JSON array of the players written together, so both players
+// of a match are saved by a single write. When the file is opened, later lines replace
+// earlier ones, and an incomplete last line left by a crash is discarded.
+//
+// The file only grows, so it should be compacted from time to time with Compact.
+type FileStore struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	players map[string]PlayerRecord
+}
+
+// Opens the store at the given path, creating the file if it does not exist.
+func OpenFileStore(path string) (*FileStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	s := &FileStore{
+		path:    path,
+		file:    f,
+		players: make(map[string]PlayerRecord),
+	}
+	if err := s.load(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reads every line of the file, truncating an incomplete last line so that later
+// writes start on a new line.
+func (s *FileStore) load() error {
+	data, err := io.ReadAll(s.file)
+	if err != nil {
+		return err
+	}
+	// anything after the last newline was never fully written
+	complete := bytes.LastIndexByte(data, '\n') + 1
+	for _, line := range bytes.Split(data[:complete], []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var batch []PlayerRecord
+		if err := json.Unmarshal(line, &batch); err != nil {
+			return ErrCorruptStore
+		}
+		for _, p := range batch {
+			s.players[p.ID] = p
+		}
+	}
+	if complete < len(data) {
+		return s.file.Truncate(int64(complete))
+	}
+	return nil
+}
+
+// Appends the players to the file as a single line.
+func (s *FileStore) write(players ...PlayerRecord) error {
+	line, err := json.Marshal(players)
+	if err != nil {
+		return err
+	}
+	end, err := s.file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		// remove any partial line so that later writes are not appended to it
+		s.file.Truncate(end)
+		return err
+	}
+	return s.file.Sync()
+}
+
+func (s *FileStore) Get(id string) (PlayerRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.players[id]
+	if !ok {
+		return PlayerRecord{}, ErrPlayerNotFound
+	}
+	return p, nil
+}
+
+func (s *FileStore) Put(p PlayerRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.write(p); err != nil {
+		return err
+	}
+	s.players[p.ID] = p
+	return nil
+}
+
+func (s *FileStore) List() ([]PlayerRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedRecords(s.players), nil
+}
+
+func (s *FileStore) Update(id1, id2 string, fn func(p1, p2 *PlayerRecord) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p1, p2, err := loadPair(s.players, id1, id2)
+	if err != nil {
+		return err
+	}
+	if err := fn(&p1, &p2); err != nil {
+		return err
+	}
+	p1.ID, p2.ID = id1, id2
+	if err := s.write(p1, p2); err != nil {
+		return err
+	}
+	s.players[id1] = p1
+	s.players[id2] = p2
+	return nil
+}
+
+// Rewrites the file with a single line per player, replacing the old file only once
+// the new one has been fully written. The new file keeps the old file's permissions.
+func (s *FileStore) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := s.file.Stat()
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".elo-store-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	w := bufio.NewWriter(tmp)
+	for _, p := range sortedRecords(s.players) {
+		line, err := json.Marshal([]PlayerRecord{p})
+		if err != nil {
+			tmp.Close()
+			return err
+		}
+		w.Write(append(line, '\n'))
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.path, os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	s.file.Close()
+	s.file = f
+	return nil
+}
+
+// Closes the underlying file. The store must not be used afterwards.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
@@ -0,0 +1,90 @@
+package elo_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "players.jsonl")
+	s, err := elo.OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testPlayerStore(t, s)
+	s.Close()
+
+	// ratings are loaded back from the file
+	s, err = elo.OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a, _ := s.Get("alice"); !almostEqual(a.Elo, 1592.311902) || a.GamesPlayed != 1 {
+		t.Fail()
+		t.Logf("Expected %f after reopening, got %f\n", 1592.311902, a.Elo)
+	}
+
+	if err := os.Chmod(path, 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0640 {
+		t.Fail()
+		t.Log("Expected compacting to keep the file's permissions")
+	}
+	data, _ := os.ReadFile(path)
+	if n := bytes.Count(data, []byte{'\n'}); n != 2 {
+		t.Fail()
+		t.Logf("Expected 2 lines after compacting, got %d\n", n)
+	}
+	s.Put(elo.PlayerRecord{ID: "carol", Elo: 1500})
+	s.Close()
+
+	s, _ = elo.OpenFileStore(path)
+	players, _ := s.List()
+	if len(players) != 3 {
+		t.Fail()
+		t.Logf("Expected 3 players after compacting, got %d\n", len(players))
+	}
+	s.Close()
+}
+
+func TestFileStoreTruncatedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "players.jsonl")
+	data := `[{"id":"alice","elo":1500}]` + "\n" + `[{"id":"alice","elo":1600},{"id":"bo`
+	os.WriteFile(path, []byte(data), 0644)
+
+	s, err := elo.OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a, _ := s.Get("alice"); !almostEqual(a.Elo, 1500) {
+		t.Fail()
+		t.Logf("Expected the incomplete line to be ignored, got %f\n", a.Elo)
+	}
+	s.Put(elo.PlayerRecord{ID: "bob", Elo: 1700})
+	s.Close()
+
+	s, err = elo.OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := s.Get("bob"); !almostEqual(b.Elo, 1700) {
+		t.Fail()
+		t.Logf("Expected %f, got %f\n", 1700.0, b.Elo)
+	}
+	s.Close()
+
+	// only the last line may be incomplete
+	os.WriteFile(path, []byte(`[{"id":"alice"`+"\n"+`[]`+"\n"), 0644)
+	if _, err := elo.OpenFileStore(path); err != elo.ErrCorruptStore {
+		t.Fail()
+		t.Logf("Expected ErrCorruptStore, got %v\n", err)
+	}
+}
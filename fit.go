@@ -0,0 +1,159 @@
+package elo
+
+import (
+	"math"
+)
+
+// The range a parameter is searched over. A range where Max is not greater than Min,
+// such as the zero range, is not searched, and the parameter keeps the builder's value.
+type ParameterRange struct {
+	Min float64
+	Max float64
+}
+
+// Which parameters Fit searches over.
+type FitOptions struct {
+	KValue      ParameterRange
+	Deviation   ParameterRange
+	ScoreWeight ParameterRange
+
+	// Whether to try both rating and ignoring draws.
+	IgnoreDraws bool
+
+	// The elo every player starts at. If 0, the default of 1500 is used.
+	Initial float64
+
+	// The number of times every parameter is searched in turn. Parameters depend on each
+	// other, so later rounds refine the earlier ones. If not positive, the default of 3
+	// is used.
+	Rounds int
+}
+
+// Returns the options Fit uses when none are given: K between 1 and 100, deviation
+// between 100 and 1000, score weight between 0 and 1, both rating and ignoring draws,
+// players starting at 1500, and 3 rounds.
+func DefaultFitOptions() *FitOptions {
+	return &FitOptions{
+		KValue:      ParameterRange{1, 100},
+		Deviation:   ParameterRange{100, 1000},
+		ScoreWeight: ParameterRange{0, 1},
+		IgnoreDraws: true,
+		Initial:     1500,
+		Rounds:      3,
+	}
+}
+
+// The parameters found by Fit and how well they predict.
+type FitResult struct {
+	KValue      float64
+	Deviation   float64
+	ScoreWeight float64
+	IgnoreDraws bool
+
+	// The backtest of the training log using the parameters found.
+	Train *BacktestReport
+
+	// The backtest of the held out log using the parameters found.
+	Test *BacktestReport
+}
+
+// Searches for the parameters that minimize the log loss of backtesting the training
+// log, and sets them on the builder. The test log is held out from the search, and is
+// backtested with the parameters found to show how well they predict matches they were
+// not fitted to. Parameters not being searched, such as the strategy, are kept from the
+// builder. If opts is nil, DefaultFitOptions is used.
+//
+// Each parameter is searched with a golden-section search while the others are held
+// fixed, which finds the best value when the log loss has a single minimum in the range.
+//
+// Note: When every player starts at the same elo, the default strategy's predictions
+// only depend on the ratio of K to the deviation, so many pairs of values fit equally well.
+func (b *CalculatorBuilder) Fit(train, test []HistoricalMatch, opts *FitOptions) *FitResult {
+	defaults := DefaultFitOptions()
+	if opts == nil {
+		opts = defaults
+	}
+	o := *opts
+	if o.Initial == 0 {
+		o.Initial = defaults.Initial
+	}
+	if o.Rounds <= 0 {
+		o.Rounds = defaults.Rounds
+	}
+	opts = &o
+	ignore := []bool{b.c.ignoreDraws}
+	if opts.IgnoreDraws {
+		ignore = []bool{false, true}
+	}
+
+	best := b.c
+	bestLoss := math.Inf(1)
+	for _, ignoreDraws := range ignore {
+		c := b.c
+		c.ignoreDraws = ignoreDraws
+		loss := fitParameters(&c, train, opts)
+		if loss < bestLoss {
+			best, bestLoss = c, loss
+		}
+	}
+
+	b.c = best
+	return &FitResult{
+		KValue:      best.k,
+		Deviation:   best.deviation,
+		ScoreWeight: best.scoreWeight,
+		IgnoreDraws: best.ignoreDraws,
+		Train:       best.Backtest(train, opts.Initial),
+		Test:        best.Backtest(test, opts.Initial),
+	}
+}
+
+// Searches every parameter in turn, leaving the best values found in c. Returns the
+// training log loss of those values.
+func fitParameters(c *Calculator, train []HistoricalMatch, opts *FitOptions) float64 {
+	params := []struct {
+		r ParameterRange
+		v *float64
+	}{
+		{opts.KValue, &c.k},
+		{opts.Deviation, &c.deviation},
+		{ParameterRange{math.Max(opts.ScoreWeight.Min, 0), math.Min(opts.ScoreWeight.Max, 1)}, &c.scoreWeight},
+	}
+	loss := func() float64 {
+		return c.Backtest(train, opts.Initial).LogLoss
+	}
+
+	for round := 0; round < opts.Rounds; round++ {
+		for _, p := range params {
+			if p.r.Max <= p.r.Min {
+				continue
+			}
+			*p.v = goldenSection(p.r, func(x float64) float64 {
+				*p.v = x
+				return loss()
+			})
+		}
+	}
+	return loss()
+}
+
+// Returns the value in the range that minimizes f, assuming f has a single minimum.
+func goldenSection(r ParameterRange, f func(float64) float64) float64 {
+	invPhi := (math.Sqrt(5) - 1) / 2
+	lo, hi := r.Min, r.Max
+	x1 := hi - invPhi*(hi-lo)
+	x2 := lo + invPhi*(hi-lo)
+	f1, f2 := f(x1), f(x2)
+	for hi-lo > (r.Max-r.Min)*1e-3 {
+		if f1 < f2 {
+			hi, x2, f2 = x2, x1, f1
+			x1 = hi - invPhi*(hi-lo)
+			f1 = f(x1)
+		} else {
+			lo, x1, f1 = x1, x2, f2
+			x2 = lo + invPhi*(hi-lo)
+			f2 = f(x2)
+		}
+	}
+	return (lo + hi) / 2
+}
@@ -0,0 +1,87 @@
+package elo_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+// Returns a log of matches between players with fixed true ratings.
+func syntheticLog(seed int64, n int) []elo.HistoricalMatch {
+	rng := rand.New(rand.NewSource(seed))
+	log := make([]elo.HistoricalMatch, n)
+	for i := range log {
+		a, b := rng.Intn(20), rng.Intn(19)
+		if b >= a {
+			b++
+		}
+		// true ratings are 50 elo apart
+		E := 1 / (1 + math.Pow(10, float64(b-a)*50/400))
+		outcome := elo.OutcomePlayerTwoWin
+		if rng.Float64() < E {
+			outcome = elo.OutcomePlayerOneWin
+		}
+		log[i] = elo.HistoricalMatch{
+			PlayerOne: fmt.Sprint(a),
+			PlayerTwo: fmt.Sprint(b),
+			Result:    elo.MatchResult{Outcome: outcome},
+		}
+	}
+	return log
+}
+
+func TestFit(t *testing.T) {
+	train := syntheticLog(1, 2000)
+	test := syntheticLog(2, 500)
+
+	b := elo.NewCalculatorBuilder().WithKValue(100)
+	baseline := b.Build().Backtest(train, 1500)
+
+	opts := elo.DefaultFitOptions()
+	opts.Deviation = elo.ParameterRange{} // only the ratio of K to deviation matters
+	r := b.Fit(train, test, opts)
+
+	if r.Train.LogLoss >= baseline.LogLoss {
+		t.Fail()
+		t.Logf("Expected fitting to improve log loss %f, got %f\n", baseline.LogLoss, r.Train.LogLoss)
+	}
+	if r.Test.Matches != 500 || r.Test.LogLoss >= math.Ln2 {
+		t.Fail()
+		t.Logf("Expected held out log loss better than even odds, got %f\n", r.Test.LogLoss)
+	}
+	if r.KValue < 1 || r.KValue > 100 || !almostEqual(r.Deviation, 400) {
+		t.Fail()
+		t.Logf("Expected K in range and deviation unchanged, got %f and %f\n", r.KValue, r.Deviation)
+	}
+
+	// the parameters found are set on the builder
+	m := b.Build().NewMatch(&player{1500}, &player{1500})
+	if !almostEqual(m.GetKValue(), r.KValue) || m.GetIgnoreDraws() != r.IgnoreDraws {
+		t.Fail()
+		t.Logf("Expected builder K %f, got %f\n", r.KValue, m.GetKValue())
+	}
+	if again := b.Build().Backtest(train, 1500); !almostEqual(again.LogLoss, r.Train.LogLoss) {
+		t.Fail()
+		t.Logf("Expected log loss %f, got %f\n", r.Train.LogLoss, again.LogLoss)
+	}
+}
+
+func TestFitZeroOptions(t *testing.T) {
+	train := syntheticLog(1, 500)
+
+	// zero rounds and a zero initial elo use the defaults
+	b := elo.NewCalculatorBuilder().WithKValue(100)
+	r := b.Fit(train, nil, &elo.FitOptions{KValue: elo.ParameterRange{Min: 1, Max: 100}})
+	want := elo.NewCalculatorBuilder().WithKValue(100).Fit(train, nil, &elo.FitOptions{
+		KValue:  elo.ParameterRange{Min: 1, Max: 100},
+		Initial: 1500,
+		Rounds:  3,
+	})
+	if almostEqual(r.KValue, 100) || !almostEqual(r.KValue, want.KValue) {
+		t.Fail()
+		t.Logf("Expected K %f, got %f\n", want.KValue, r.KValue)
+	}
+}
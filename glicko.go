@@ -0,0 +1,109 @@
+package elo
+
+import (
+	"math"
+)
+
+// Default Glicko constant c. With this value, the rating deviation of a player
+// with a deviation of 50 will return to 350 after 100 rating periods of inactivity.
+const DefaultGlickoC = 34.6
+
+// An IdlePlayer is a Player that reports how many rating periods have passed since
+// it last played. Glicko strategies use this to increase the player's rating
+// deviation before the match is rated.
+type IdlePlayer interface {
+	Player
+	GetIdlePeriods() float64
+}
+
+// Set the Glicko constant c, which determines how quickly rating deviation grows
+// while a player is inactive. Must be non-negative. Providing a negative value
+// will result in no change. Default is 34.6.
+func (b *CalculatorBuilder) WithGlickoC(c float64) *CalculatorBuilder {
+	if c < 0 {
+		return b
+	}
+	b.c.glickoC = c
+	return b
+}
+
+// Calculates new ratings using the original Glicko system, treating the match as a
+// rating period of a single game. The new rating deviations are written back into
+// the input.
+func StrategyGlicko(input *CalculatorInput) (float64, float64) {
+	p1 := GlickoRating{
+		Rating:          input.PlayerOne,
+		RatingDeviation: orDefault(input.PlayerOneRatingDeviation, DefaultRatingDeviation),
+	}
+	p2 := GlickoRating{
+		Rating:          input.PlayerTwo,
+		RatingDeviation: orDefault(input.PlayerTwoRatingDeviation, DefaultRatingDeviation),
+	}
+	p1 = glickoInactivity(p1, input.PlayerOneIdlePeriods, input.C)
+	p2 = glickoInactivity(p2, input.PlayerTwoIdlePeriods, input.C)
+	S1, S2 := outcomeScores(input.Outcome)
+
+	// player one's advantage is taken off player two's rating in player one's expectation,
+	// and added to player one's rating in player two's
+	n1 := glickoPeriod(p1, []GlickoResult{{Opponent: shifted(p2, -input.Advantage), Score: S1}}, input.Deviation)
+	n2 := glickoPeriod(p2, []GlickoResult{{Opponent: shifted(p1, input.Advantage), Score: S2}}, input.Deviation)
+
+	input.PlayerOneRatingDeviation = n1.RatingDeviation
+	input.PlayerTwoRatingDeviation = n2.RatingDeviation
+	return n1.Rating, n2.Rating
+}
+
+// Rates a player over a whole Glicko rating period, using the calculator's deviation.
+// The player's rating deviation should already reflect the start of the period; see
+// GlickoInactivity. Volatility is ignored and returned unchanged.
+func (c *Calculator) GlickoPeriod(player GlickoRating, results []GlickoResult) GlickoRating {
+	return glickoPeriod(player, results, c.deviation)
+}
+
+// Returns the player's rating with its rating deviation increased to account for the
+// given number of rating periods without a game, using the calculator's constant c.
+// The rating deviation never exceeds DefaultRatingDeviation.
+func (c *Calculator) GlickoInactivity(player GlickoRating, periods float64) GlickoRating {
+	return glickoInactivity(player, periods, c.glickoC)
+}
+
+func glickoInactivity(player GlickoRating, periods, c float64) GlickoRating {
+	if periods <= 0 {
+		return player
+	}
+	rd := math.Sqrt(player.RatingDeviation*player.RatingDeviation + c*c*periods)
+	player.RatingDeviation = math.Min(rd, DefaultRatingDeviation)
+	return player
+}
+
+func glickoPeriod(player GlickoRating, results []GlickoResult, deviation float64) GlickoRating {
+	if len(results) == 0 {
+		return player
+	}
+	q := math.Ln10 / deviation
+
+	var d2Inv, sum float64
+	for _, r := range results {
+		g := glickoG(q, r.Opponent.RatingDeviation)
+		E := 1 / (1 + math.Pow(10, -g*(player.Rating-r.Opponent.Rating)/deviation))
+		d2Inv += q * q * g * g * E * (1 - E)
+		sum += g * (r.Score - E)
+	}
+
+	rd2 := 1 / (1/(player.RatingDeviation*player.RatingDeviation) + d2Inv)
+	return GlickoRating{
+		Rating:          player.Rating + q*rd2*sum,
+		RatingDeviation: math.Sqrt(rd2),
+		Volatility:      player.Volatility,
+	}
+}
+
+// Returns the rating moved by the given elo.
+func shifted(r GlickoRating, elo float64) GlickoRating {
+	r.Rating += elo
+	return r
+}
+
+func glickoG(q, rd float64) float64 {
+	return 1 / math.Sqrt(1+3*q*q*rd*rd/(math.Pi*math.Pi))
+}
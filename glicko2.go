@@ -0,0 +1,208 @@
+package elo
+
+import (
+	"math"
+)
+
+const (
+	// Rating deviation given to players whose deviation is unknown.
+	DefaultRatingDeviation = 350.0
+
+	// Volatility given to players whose volatility is unknown.
+	DefaultVolatility = 0.06
+
+	// Default system constant for Glicko-2. Smaller values prevent the volatility
+	// from changing by large amounts.
+	DefaultTau = 0.5
+
+	// Convergence tolerance used when solving for the new volatility.
+	glicko2Epsilon = 0.000001
+)
+
+// A GlickoPlayer is a Player that also keeps track of how uncertain its rating is.
+// When both players of a match implement GlickoPlayer, their rating deviation and
+// volatility are passed to the strategy and updated after the match is played.
+type GlickoPlayer interface {
+	Player
+	GetRatingDeviation() float64
+	SetRatingDeviation(float64)
+	GetVolatility() float64
+	SetVolatility(float64)
+}
+
+// A player's full rating as used by the Glicko systems.
+type GlickoRating struct {
+	Rating          float64
+	RatingDeviation float64
+	Volatility      float64
+}
+
+// A single game played during a rating period.
+type GlickoResult struct {
+	Opponent GlickoRating
+
+	// 1 for a win, 0.5 for a draw and 0 for a loss.
+	Score float64
+}
+
+// Set the Glicko-2 system constant tau. Reasonable values are between 0.3 and 1.2.
+// Must be greater than 0. Providing a non-positive value will result in no change.
+// Default is 0.5.
+func (b *CalculatorBuilder) WithTau(tau float64) *CalculatorBuilder {
+	if tau <= 0 {
+		return b
+	}
+	b.c.tau = tau
+	return b
+}
+
+// Calculates new ratings using the Glicko-2 system, treating the match as a rating
+// period of a single game. The new rating deviations and volatilities are written back
+// into the input.
+func StrategyGlicko2(input *CalculatorInput) (float64, float64) {
+	tau := input.Tau
+	if tau <= 0 {
+		tau = DefaultTau
+	}
+	p1 := GlickoRating{
+		Rating:          input.PlayerOne,
+		RatingDeviation: orDefault(input.PlayerOneRatingDeviation, DefaultRatingDeviation),
+		Volatility:      orDefault(input.PlayerOneVolatility, DefaultVolatility),
+	}
+	p2 := GlickoRating{
+		Rating:          input.PlayerTwo,
+		RatingDeviation: orDefault(input.PlayerTwoRatingDeviation, DefaultRatingDeviation),
+		Volatility:      orDefault(input.PlayerTwoVolatility, DefaultVolatility),
+	}
+	p1 = glicko2Inactivity(p1, input.PlayerOneIdlePeriods, input.Deviation)
+	p2 = glicko2Inactivity(p2, input.PlayerTwoIdlePeriods, input.Deviation)
+	S1, S2 := outcomeScores(input.Outcome)
+
+	n1 := glicko2Period(p1, []GlickoResult{{Opponent: shifted(p2, -input.Advantage), Score: S1}}, tau, input.Deviation)
+	n2 := glicko2Period(p2, []GlickoResult{{Opponent: shifted(p1, input.Advantage), Score: S2}}, tau, input.Deviation)
+
+	input.PlayerOneRatingDeviation = n1.RatingDeviation
+	input.PlayerTwoRatingDeviation = n2.RatingDeviation
+	input.PlayerOneVolatility = n1.Volatility
+	input.PlayerTwoVolatility = n2.Volatility
+	return n1.Rating, n2.Rating
+}
+
+// Rates a player over a whole Glicko-2 rating period, using the calculator's tau and
+// deviation. If the player did not play during the period, only their rating deviation
+// increases. As in StrategyGlicko2, a rating deviation or volatility of 0 is treated as
+// unknown, and DefaultRatingDeviation or DefaultVolatility is used instead.
+func (c *Calculator) Glicko2Period(player GlickoRating, results []GlickoResult) GlickoRating {
+	player.RatingDeviation = orDefault(player.RatingDeviation, DefaultRatingDeviation)
+	player.Volatility = orDefault(player.Volatility, DefaultVolatility)
+	return glicko2Period(player, results, c.tau, c.deviation)
+}
+
+func glicko2Period(player GlickoRating, results []GlickoResult, tau, deviation float64) GlickoRating {
+	// the glicko-2 scale is 173.7178 for the conventional deviation of 400
+	scale := deviation / math.Ln10
+	phi := player.RatingDeviation / scale
+	sigma := player.Volatility
+
+	if len(results) == 0 {
+		return GlickoRating{
+			Rating:          player.Rating,
+			RatingDeviation: math.Sqrt(phi*phi+sigma*sigma) * scale,
+			Volatility:      sigma,
+		}
+	}
+
+	// estimated variance (v) and improvement (delta) based on game outcomes
+	var vInv, sum float64
+	for _, r := range results {
+		muJ := (r.Opponent.Rating - player.Rating) / scale
+		g := glicko2G(r.Opponent.RatingDeviation / scale)
+		E := 1 / (1 + math.Exp(g*muJ))
+		vInv += g * g * E * (1 - E)
+		sum += g * (r.Score - E)
+	}
+	v := 1 / vInv
+	delta := v * sum
+
+	sigma = glicko2Volatility(phi, sigma, v, delta, tau)
+
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phi = 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+
+	return GlickoRating{
+		Rating:          player.Rating + phi*phi*sum*scale,
+		RatingDeviation: phi * scale,
+		Volatility:      sigma,
+	}
+}
+
+// Increases the rating deviation by the player's volatility once for every rating
+// period without a game, the same way glicko2Period does for a single empty period.
+func glicko2Inactivity(player GlickoRating, periods, deviation float64) GlickoRating {
+	if periods <= 0 {
+		return player
+	}
+	scale := deviation / math.Ln10
+	phi := player.RatingDeviation / scale
+	phi = math.Sqrt(phi*phi + periods*player.Volatility*player.Volatility)
+	player.RatingDeviation = math.Min(phi*scale, DefaultRatingDeviation)
+	return player
+}
+
+func glicko2G(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+// Finds the new volatility using the Illinois algorithm, as described in step 5 of
+// Glickman's "Example of the Glicko-2 system".
+func glicko2Volatility(phi, sigma, v, delta, tau float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(tau*tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > glicko2Epsilon {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA = fA / 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
+
+// Returns the actual scores of player one and two for a win/loss outcome.
+func outcomeScores(o MatchOutcome) (S1, S2 float64) {
+	switch o {
+	case OutcomePlayerOneWin:
+		return 1, 0
+	case OutcomePlayerTwoWin:
+		return 0, 1
+	}
+	return 0.5, 0.5
+}
+
+func orDefault(v, def float64) float64 {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
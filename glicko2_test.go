@@ -0,0 +1,131 @@
+package elo_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+type glickoPlayer struct {
+	player
+	rd  float64
+	vol float64
+}
+
+func (p *glickoPlayer) GetRatingDeviation() float64 {
+	return p.rd
+}
+func (p *glickoPlayer) SetRatingDeviation(rd float64) {
+	p.rd = rd
+}
+func (p *glickoPlayer) GetVolatility() float64 {
+	return p.vol
+}
+func (p *glickoPlayer) SetVolatility(v float64) {
+	p.vol = v
+}
+
+func TestGlicko2Period(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+
+	// example from Glickman's "Example of the Glicko-2 system"
+	n := c.Glicko2Period(elo.GlickoRating{
+		Rating:          1500,
+		RatingDeviation: 200,
+		Volatility:      0.06,
+	}, []elo.GlickoResult{
+		{Opponent: elo.GlickoRating{Rating: 1400, RatingDeviation: 30}, Score: 1},
+		{Opponent: elo.GlickoRating{Rating: 1550, RatingDeviation: 100}, Score: 0},
+		{Opponent: elo.GlickoRating{Rating: 1700, RatingDeviation: 300}, Score: 0},
+	})
+
+	if math.Abs(n.Rating-1464.06) > 0.01 {
+		t.Fail()
+		t.Logf("Expected rating %f, got %f\n", 1464.06, n.Rating)
+	}
+	if math.Abs(n.RatingDeviation-151.52) > 0.01 {
+		t.Fail()
+		t.Logf("Expected rating deviation %f, got %f\n", 151.52, n.RatingDeviation)
+	}
+	if math.Abs(n.Volatility-0.05999) > 0.00001 {
+		t.Fail()
+		t.Logf("Expected volatility %f, got %f\n", 0.05999, n.Volatility)
+	}
+
+	// a player who does not compete only becomes less certain
+	n = c.Glicko2Period(elo.GlickoRating{
+		Rating:          1500,
+		RatingDeviation: 200,
+		Volatility:      0.06,
+	}, nil)
+
+	if !almostEqual(n.Rating, 1500) || !almostEqual(n.Volatility, 0.06) {
+		t.Fail()
+		t.Logf("Rating and volatility must not change without games, got %f and %f\n", n.Rating, n.Volatility)
+	}
+	if !almostEqual(n.RatingDeviation, 200.271417) {
+		t.Fail()
+		t.Logf("Expected rating deviation %f, got %f\n", 200.271417, n.RatingDeviation)
+	}
+
+	// an unknown volatility is given the default
+	n = c.Glicko2Period(elo.GlickoRating{Rating: 1500, RatingDeviation: 200}, nil)
+	if !almostEqual(n.Volatility, elo.DefaultVolatility) || !almostEqual(n.RatingDeviation, 200.271417) {
+		t.Fail()
+		t.Logf("Expected volatility %f and rating deviation %f, got %f and %f\n", elo.DefaultVolatility, 200.271417,
+			n.Volatility, n.RatingDeviation)
+	}
+}
+
+func TestGlicko2Strategy(t *testing.T) {
+	c := elo.NewCalculatorBuilder().
+		WithStrategy(elo.StrategyGlicko2).
+		WithTau(0.5).
+		WithTau(-1). // will be ignored
+		Build()
+
+	p1 := &glickoPlayer{player{1500}, 200, 0.06}
+	p2 := &glickoPlayer{player{1400}, 30, 0.06}
+
+	m := c.NewMatch(p1, p2)
+	m.Play(&elo.MatchResult{
+		Outcome: elo.OutcomePlayerOneWin,
+	})
+
+	if !almostEqual(p1.elo, 1563.564195) {
+		t.Fail()
+		t.Logf("Expected P1 Elo %f, got %f\n", 1563.564195, p1.elo)
+	}
+	if !almostEqual(p2.elo, 1398.143558) {
+		t.Fail()
+		t.Logf("Expected P2 Elo %f, got %f\n", 1398.143558, p2.elo)
+	}
+	if p1.rd >= 200 {
+		t.Fail()
+		t.Logf("Rating deviation must shrink after playing, got %f\n", p1.rd)
+	}
+	if !almostEqual(p1.rd, 175.402654) {
+		t.Fail()
+		t.Logf("Expected P1 RD %f, got %f\n", 175.402654, p1.rd)
+	}
+
+	// players without a rating deviation use the defaults
+	n1, n2 := c.Calculate(1500, 1500, &elo.MatchResult{
+		Outcome: elo.OutcomePlayerTwoWin,
+	})
+	if !almostEqual(n1-1500, 1500-n2) {
+		t.Fail()
+		t.Log("Equal players with equal uncertainty must gain and lose equal amounts")
+	}
+
+	g1, g2 := c.CalculateGlicko(
+		elo.GlickoRating{Rating: 1500, RatingDeviation: 200, Volatility: 0.06},
+		elo.GlickoRating{Rating: 1400, RatingDeviation: 30, Volatility: 0.06},
+		&elo.MatchResult{Outcome: elo.OutcomePlayerOneWin},
+	)
+	if !almostEqual(g1.Rating, p1.elo) || !almostEqual(g2.RatingDeviation, p2.rd) {
+		t.Fail()
+		t.Log("CalculateGlicko must agree with Match.Play")
+	}
+}
@@ -0,0 +1,117 @@
+package elo_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+type idlePlayer struct {
+	glickoPlayer
+	idle float64
+}
+
+func (p *idlePlayer) GetIdlePeriods() float64 {
+	return p.idle
+}
+
+func TestGlickoPeriod(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+
+	// example from Glickman's "The Glicko system"
+	n := c.GlickoPeriod(elo.GlickoRating{
+		Rating:          1500,
+		RatingDeviation: 200,
+	}, []elo.GlickoResult{
+		{Opponent: elo.GlickoRating{Rating: 1400, RatingDeviation: 30}, Score: 1},
+		{Opponent: elo.GlickoRating{Rating: 1550, RatingDeviation: 100}, Score: 0},
+		{Opponent: elo.GlickoRating{Rating: 1700, RatingDeviation: 300}, Score: 0},
+	})
+
+	if math.Abs(n.Rating-1464) > 0.5 {
+		t.Fail()
+		t.Logf("Expected rating %f, got %f\n", 1464.0, n.Rating)
+	}
+	if math.Abs(n.RatingDeviation-151.4) > 0.05 {
+		t.Fail()
+		t.Logf("Expected rating deviation %f, got %f\n", 151.4, n.RatingDeviation)
+	}
+}
+
+func TestGlickoInactivity(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+
+	r := elo.GlickoRating{Rating: 1500, RatingDeviation: 50}
+
+	n := c.GlickoInactivity(r, 0)
+	if !almostEqual(n.RatingDeviation, 50) {
+		t.Fail()
+		t.Logf("Expected rating deviation %f, got %f\n", 50.0, n.RatingDeviation)
+	}
+
+	n = c.GlickoInactivity(r, 100)
+	if !almostEqual(n.RatingDeviation, 349.594050) {
+		t.Fail()
+		t.Logf("Expected rating deviation %f, got %f\n", 349.594050, n.RatingDeviation)
+	}
+
+	n = c.GlickoInactivity(r, 1000)
+	if !almostEqual(n.RatingDeviation, elo.DefaultRatingDeviation) {
+		t.Fail()
+		t.Logf("Rating deviation must not exceed %f, got %f\n", elo.DefaultRatingDeviation, n.RatingDeviation)
+	}
+
+	c = elo.NewCalculatorBuilder().
+		WithGlickoC(63.2).
+		WithGlickoC(-1). // will be ignored
+		Build()
+
+	n = c.GlickoInactivity(r, 30)
+	if math.Abs(n.RatingDeviation-350) > 0.5 {
+		t.Fail()
+		t.Logf("Expected rating deviation %f, got %f\n", 350.0, n.RatingDeviation)
+	}
+}
+
+func TestGlickoStrategy(t *testing.T) {
+	c := elo.NewCalculatorBuilder().
+		WithStrategy(elo.StrategyGlicko).
+		Build()
+
+	p1 := &idlePlayer{glickoPlayer{player{1500}, 200, 0}, 0}
+	p2 := &idlePlayer{glickoPlayer{player{1400}, 30, 0}, 0}
+
+	m := c.NewMatch(p1, p2)
+	m.Play(&elo.MatchResult{
+		Outcome: elo.OutcomePlayerOneWin,
+	})
+
+	if !almostEqual(p1.elo, 1563.432049) {
+		t.Fail()
+		t.Logf("Expected P1 Elo %f, got %f\n", 1563.432049, p1.elo)
+	}
+	if !almostEqual(p1.rd, 175.220234) {
+		t.Fail()
+		t.Logf("Expected P1 RD %f, got %f\n", 175.220234, p1.rd)
+	}
+
+	// the same match after a long absence moves the idle player's rating further
+	o1 := p1.elo - 1500
+	p1.elo, p1.rd, p1.idle = 1500, 200, 20
+	p2.elo, p2.rd = 1400, 30
+
+	m = c.NewMatch(p1, p2)
+	m.Play(&elo.MatchResult{
+		Outcome: elo.OutcomePlayerOneWin,
+	})
+
+	if p1.elo-1500 <= o1 {
+		t.Fail()
+		t.Logf("Inactive player must gain more than %f, gained %f\n", o1, p1.elo-1500)
+	}
+	if p1.rd <= 175.220234 {
+		t.Fail()
+		t.Logf("Inactive player must finish with a larger rating deviation, got %f\n", p1.rd)
+	}
+}
@@ -0,0 +1,148 @@
+package elo_test
+
+import (
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+type experiencedPlayer struct {
+	player
+	games int
+	peak  float64
+}
+
+func (p *experiencedPlayer) GetGamesPlayed() int {
+	return p.games
+}
+func (p *experiencedPlayer) GetPeakElo() float64 {
+	return p.peak
+}
+
+func TestKFactorFIDE(t *testing.T) {
+	tests := []struct {
+		input    elo.KFactorInput
+		expected float64
+	}{
+		{elo.KFactorInput{Elo: 1500, GamesPlayed: 10, PeakElo: 1500}, 40},
+		{elo.KFactorInput{Elo: 2500, GamesPlayed: 29, PeakElo: 2500}, 40},
+		{elo.KFactorInput{Elo: 2300, GamesPlayed: 30, PeakElo: 2350}, 20},
+		{elo.KFactorInput{Elo: 2300, GamesPlayed: 200, PeakElo: 2410}, 10},
+		{elo.KFactorInput{Elo: 2450, GamesPlayed: 200, PeakElo: 0}, 10},
+	}
+	for _, test := range tests {
+		if k := elo.KFactorFIDE(&test.input); !almostEqual(k, test.expected) {
+			t.Fail()
+			t.Logf("Expected K %f for %+v, got %f\n", test.expected, test.input, k)
+		}
+	}
+}
+
+func TestKFactorUSCF(t *testing.T) {
+	tests := []struct {
+		input    elo.KFactorInput
+		expected float64
+	}{
+		{elo.KFactorInput{Elo: 1500, GamesPlayed: 10}, 72.727273},
+		{elo.KFactorInput{Elo: 1500, GamesPlayed: 100}, 45.536138},
+		{elo.KFactorInput{Elo: 2400, GamesPlayed: 100}, 15.686275},
+	}
+	for _, test := range tests {
+		if k := elo.KFactorUSCF(&test.input); !almostEqual(k, test.expected) {
+			t.Fail()
+			t.Logf("Expected K %f for %+v, got %f\n", test.expected, test.input, k)
+		}
+	}
+}
+
+func TestWithKFactor(t *testing.T) {
+	c := elo.NewCalculatorBuilder().
+		WithKFactor(elo.KFactorFIDE).
+		Build()
+
+	p1 := &experiencedPlayer{player{1600}, 5, 1600}
+	p2 := &experiencedPlayer{player{1800}, 100, 1850}
+
+	m := c.NewMatch(p1, p2)
+	m.Play(&elo.MatchResult{
+		Outcome: elo.OutcomePlayerOneWin,
+	})
+
+	// P1 uses K=40 and P2 uses K=20
+	if !almostEqual(p1.elo, 1630.389877) {
+		t.Fail()
+		t.Logf("Expected P1 Elo %f, got %f\n", 1630.389877, p1.elo)
+	}
+	if !almostEqual(p2.elo, 1784.805061) {
+		t.Fail()
+		t.Logf("Expected P2 Elo %f, got %f\n", 1784.805061, p2.elo)
+	}
+
+	// players without a history use the calculator's K
+	p3 := &player{1600}
+	p4 := &player{1800}
+	m = c.NewMatch(p3, p4)
+	if !almostEqual(m.PlayerOneGain(), 24.311902) {
+		t.Fail()
+		t.Logf("Expected P1 Gain to be %f, got %f\n", 24.311902, m.PlayerOneGain())
+	}
+
+	// a K set on the match overrides the calculator's KFactorFunc
+	p1.elo, p2.elo = 1600, 1800
+	m = c.NewMatch(p1, p2)
+	m.SetKValue(32)
+	m.Play(&elo.MatchResult{
+		Outcome: elo.OutcomePlayerTwoWin,
+	})
+	if !almostEqual(p1.elo, 1592.311901653) {
+		t.Fail()
+		t.Logf("Expected P1 Elo %f, got %f\n", 1592.311901653, p1.elo)
+	}
+}
+
+func TestKFactorTeamMatch(t *testing.T) {
+	c := elo.NewCalculatorBuilder().WithKFactor(elo.KFactorFIDE).Build()
+
+	// a new player has K 40 against the calculator's 32
+	p1 := &experiencedPlayer{player: player{1500}, games: 0, peak: 1500}
+	p2 := &player{1500}
+	p3 := &experiencedPlayer{player: player{1500}, games: 100, peak: 1500}
+	p4 := &player{1500}
+	c.NewTeamMatch([]elo.Player{p1, p2}, []elo.Player{p3, p4}).Play(&elo.MatchResult{Outcome: elo.OutcomePlayerOneWin})
+
+	if !almostEqual(p1.elo-1500, 1.25*(p2.elo-1500)) {
+		t.Fail()
+		t.Logf("Expected P1 to gain %f, got %f\n", 1.25*(p2.elo-1500), p1.elo-1500)
+	}
+	if !almostEqual(p3.elo-1500, 0.625*(p4.elo-1500)) || p3.elo >= 1500 {
+		t.Fail()
+		t.Logf("Expected P3 to lose %f, got %f\n", 0.625*(1500-p4.elo), 1500-p3.elo)
+	}
+}
+
+func TestKFactorSetKValue(t *testing.T) {
+	c := elo.NewCalculatorBuilder().WithKFactor(elo.KFactorFIDE).Build()
+
+	// a K set on the match applies to every player, whatever their K-Factor
+	p1 := &experiencedPlayer{player: player{1500}, games: 0, peak: 1500}
+	p2 := &player{1500}
+	p3 := &experiencedPlayer{player: player{1500}, games: 100, peak: 1500}
+	p4 := &player{1500}
+	tm := c.NewTeamMatch([]elo.Player{p1, p2}, []elo.Player{p3, p4})
+	tm.SetKValue(10)
+	tm.Play(&elo.MatchResult{Outcome: elo.OutcomePlayerOneWin})
+	if !almostEqual(p1.elo, p2.elo) || !almostEqual(p3.elo, p4.elo) || !almostEqual(p1.elo, 1505) {
+		t.Fail()
+		t.Logf("Expected %f and %f, got %f, %f, %f and %f\n", 1505.0, 1495.0, p1.elo, p2.elo, p3.elo, p4.elo)
+	}
+
+	p5 := &experiencedPlayer{player: player{1500}, games: 0, peak: 1500}
+	p6 := &experiencedPlayer{player: player{1500}, games: 100, peak: 1500}
+	mm := c.NewMultiMatch(p5, p6)
+	mm.SetKValue(10)
+	mm.Play([]int{1, 2})
+	if !almostEqual(p5.elo, 1505) || !almostEqual(p6.elo, 1495) {
+		t.Fail()
+		t.Logf("Expected %f and %f, got %f and %f\n", 1505.0, 1495.0, p5.elo, p6.elo)
+	}
+}
@@ -0,0 +1,389 @@
+package elo
+
+import "sync"
+
+// A player's position on a Leaderboard.
+type LeaderboardEntry struct {
+	Player Player
+	Elo    float64
+
+	// The player's position, numbered from 1, where players with the same rating share
+	// the best position among them, and the positions after them are skipped, as in 1, 2,
+	// 2, 4.
+	Rank int
+
+	// The player's position among distinct ratings, numbered from 1, so players with the
+	// same rating share a position and no positions are skipped, as in 1, 2, 2, 3.
+	DenseRank int
+}
+
+// Keeps players sorted by rating. Players are kept in a treap, a balanced search tree
+// where every node knows how many players are below it, and the distinct ratings in a
+// second treap, so adding, moving and ranking a player take logarithmic time however
+// many players there are, and however many share a rating. Players with the same rating
+// are kept in the order they reached it.
+//
+// The leaderboard reads a player's rating when they are added or updated, so matches
+// should be played through Play, PlayTeamMatch or PlayMultiMatch, or players updated
+// after their rating changes. Players are told apart by their identity, so they should
+// be pointers. A Leaderboard is safe for concurrent use.
+type Leaderboard struct {
+	mu   sync.RWMutex
+	root *leaderboardNode
+	keys map[Player]leaderboardKey
+
+	// The distinct ratings, and how many players have each.
+	distinct *leaderboardNode
+	ties     map[float64]int
+
+	next uint64
+}
+
+// Orders players by rating, highest first, then by when they reached it.
+type leaderboardKey struct {
+	elo float64
+	seq uint64
+}
+
+// A player, or a distinct rating when player is nil.
+type leaderboardNode struct {
+	key      leaderboardKey
+	player   Player
+	priority uint64
+
+	// Earlier keys are on the left.
+	left  *leaderboardNode
+	right *leaderboardNode
+
+	// The number of nodes in the subtree.
+	size int
+}
+
+func NewLeaderboard() *Leaderboard {
+	return &Leaderboard{
+		keys: make(map[Player]leaderboardKey),
+		ties: make(map[float64]int),
+	}
+}
+
+// Adds players to the leaderboard, or moves them to their current rating if they are
+// already on it. Args players should be non-nil pointers.
+func (l *Leaderboard) Update(players ...Player) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, p := range players {
+		l.update(p)
+	}
+}
+
+func (l *Leaderboard) update(p Player) {
+	elo := p.GetElo()
+	if old, ok := l.keys[p]; ok {
+		if old.elo == elo {
+			return
+		}
+		l.remove(p, old)
+	}
+	l.next++
+	key := leaderboardKey{elo: elo, seq: l.next}
+	l.keys[p] = key
+	l.root = l.root.insert(&leaderboardNode{key: key, player: p, priority: l.priority(), size: 1})
+	if l.ties[elo]++; l.ties[elo] == 1 {
+		l.distinct = l.distinct.insert(&leaderboardNode{key: leaderboardKey{elo: elo}, priority: l.priority(), size: 1})
+	}
+}
+
+func (l *Leaderboard) remove(p Player, key leaderboardKey) {
+	delete(l.keys, p)
+	l.root = l.root.remove(key)
+	if l.ties[key.elo]--; l.ties[key.elo] == 0 {
+		delete(l.ties, key.elo)
+		l.distinct = l.distinct.remove(leaderboardKey{elo: key.elo})
+	}
+}
+
+// Returns a well spread priority for a new node, so the trees stay balanced.
+func (l *Leaderboard) priority() uint64 {
+	l.next++
+	return uint64(chunkSeed(0, int(l.next)))
+}
+
+// Removes a player from the leaderboard.
+func (l *Leaderboard) Remove(p Player) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	key, ok := l.keys[p]
+	if !ok {
+		return ErrPlayerNotFound
+	}
+	l.remove(p, key)
+	return nil
+}
+
+// Returns the number of players on the leaderboard.
+func (l *Leaderboard) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.root.count()
+}
+
+// Plays the match and moves both players to their new ratings. Players who are not on
+// the leaderboard are added.
+func (l *Leaderboard) Play(m *Match, result *MatchResult) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	m.Play(result)
+	l.update(m.PlayerOne)
+	l.update(m.PlayerTwo)
+}
+
+// Plays the team match and moves every player to their new rating. Players who are not
+// on the leaderboard are added.
+func (l *Leaderboard) PlayTeamMatch(m *TeamMatch, result *MatchResult) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	m.Play(result)
+	for _, p := range m.TeamOne {
+		l.update(p)
+	}
+	for _, p := range m.TeamTwo {
+		l.update(p)
+	}
+}
+
+// Plays the free-for-all match and moves every player to their new rating. Players who
+// are not on the leaderboard are added.
+func (l *Leaderboard) PlayMultiMatch(m *MultiMatch, placements []int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := m.Play(placements); err != nil {
+		return err
+	}
+	for _, p := range m.Players {
+		l.update(p)
+	}
+	return nil
+}
+
+// Returns the player's entry, or ErrPlayerNotFound.
+func (l *Leaderboard) Entry(p Player) (LeaderboardEntry, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	key, ok := l.keys[p]
+	if !ok {
+		return LeaderboardEntry{}, ErrPlayerNotFound
+	}
+	return l.entry(p, key.elo), nil
+}
+
+// Returns the entry of a player with the rating.
+func (l *Leaderboard) entry(p Player, elo float64) LeaderboardEntry {
+	above := leaderboardKey{elo: elo}
+	return LeaderboardEntry{
+		Player:    p,
+		Elo:       elo,
+		Rank:      l.root.before(above) + 1,
+		DenseRank: l.distinct.before(above) + 1,
+	}
+}
+
+// Returns the player's rank, where players with the same rating share the best rank
+// among them. See LeaderboardEntry.
+func (l *Leaderboard) Rank(p Player) (int, error) {
+	e, err := l.Entry(p)
+	return e.Rank, err
+}
+
+// Returns the player's rank among distinct ratings. See LeaderboardEntry.
+func (l *Leaderboard) DenseRank(p Player) (int, error) {
+	e, err := l.Entry(p)
+	return e.DenseRank, err
+}
+
+// Returns the percentage of the other players on the leaderboard that the player is
+// rated above, between 0 and 100, where players with the same rating count as half.
+// The only player on a leaderboard is at 100.
+func (l *Leaderboard) Percentile(p Player) (float64, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	key, ok := l.keys[p]
+	if !ok {
+		return 0, ErrPlayerNotFound
+	}
+	n := l.root.count()
+	if n == 1 {
+		return 100, nil
+	}
+	above := l.root.before(leaderboardKey{elo: key.elo})
+	tied := l.ties[key.elo] - 1
+	below := n - 1 - above - tied
+	return 100 * (float64(below) + float64(tied)/2) / float64(n-1), nil
+}
+
+// Returns the n highest rated players, best first.
+func (l *Leaderboard) Top(n int) []LeaderboardEntry {
+	return l.Range(0, n)
+}
+
+// Returns up to n players on either side of the player, and the player, best first.
+func (l *Leaderboard) Neighbors(p Player, n int) ([]LeaderboardEntry, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	key, ok := l.keys[p]
+	if !ok {
+		return nil, ErrPlayerNotFound
+	}
+	position := l.root.before(key)
+	start := max(position-n, 0)
+	return l.entries(start, position+n+1), nil
+}
+
+// Returns count players starting from the given position, where 0 is the highest rated.
+func (l *Leaderboard) Range(start, count int) []LeaderboardEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.entries(max(start, 0), start+count)
+}
+
+func (l *Leaderboard) entries(start, end int) []LeaderboardEntry {
+	end = min(end, l.root.count())
+	if start >= end {
+		return nil
+	}
+	nodes := make([]*leaderboardNode, 0, end-start)
+	l.root.collect(start, end, 0, &nodes)
+
+	// only the first entry is ranked through the trees, as the entries after it are in
+	// order
+	entries := make([]LeaderboardEntry, len(nodes))
+	entries[0] = l.entry(nodes[0].player, nodes[0].key.elo)
+	for i := 1; i < len(nodes); i++ {
+		e := entries[i-1]
+		e.Player, e.Elo = nodes[i].player, nodes[i].key.elo
+		if e.Elo != entries[i-1].Elo {
+			e.Rank = start + i + 1
+			e.DenseRank++
+		}
+		entries[i] = e
+	}
+	return entries
+}
+
+func (a leaderboardKey) less(b leaderboardKey) bool {
+	if a.elo != b.elo {
+		return a.elo > b.elo
+	}
+	return a.seq < b.seq
+}
+
+func (n *leaderboardNode) count() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *leaderboardNode) recount() {
+	n.size = n.left.count() + 1 + n.right.count()
+}
+
+// Returns the number of nodes in the subtree with keys before the key.
+func (n *leaderboardNode) before(key leaderboardKey) int {
+	var count int
+	for n != nil {
+		if n.key.less(key) {
+			count += n.left.count() + 1
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	return count
+}
+
+// Adds the node to the subtree, returning its new root.
+func (n *leaderboardNode) insert(node *leaderboardNode) *leaderboardNode {
+	if n == nil {
+		return node
+	}
+	if node.key.less(n.key) {
+		n.left = n.left.insert(node)
+		if n.left.priority > n.priority {
+			n = n.rotateRight()
+		}
+	} else {
+		n.right = n.right.insert(node)
+		if n.right.priority > n.priority {
+			n = n.rotateLeft()
+		}
+	}
+	n.recount()
+	return n
+}
+
+// Removes the node with the key from the subtree, returning its new root.
+func (n *leaderboardNode) remove(key leaderboardKey) *leaderboardNode {
+	if n == nil {
+		return nil
+	}
+	switch {
+	case key.less(n.key):
+		n.left = n.left.remove(key)
+	case n.key.less(key):
+		n.right = n.right.remove(key)
+	default:
+		return mergeNodes(n.left, n.right)
+	}
+	n.recount()
+	return n
+}
+
+// Joins two subtrees where every key in a is before every key in b.
+func mergeNodes(a, b *leaderboardNode) *leaderboardNode {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.priority > b.priority {
+		a.right = mergeNodes(a.right, b)
+		a.recount()
+		return a
+	}
+	b.left = mergeNodes(a, b.left)
+	b.recount()
+	return b
+}
+
+func (n *leaderboardNode) rotateRight() *leaderboardNode {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	n.recount()
+	l.recount()
+	return l
+}
+
+func (n *leaderboardNode) rotateLeft() *leaderboardNode {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	n.recount()
+	r.recount()
+	return r
+}
+
+// Appends the nodes at positions start to end of the subtree, excluding end, given the
+// position of its first node.
+func (n *leaderboardNode) collect(start, end, position int, nodes *[]*leaderboardNode) {
+	if n == nil || position >= end || position+n.size <= start {
+		return
+	}
+	n.left.collect(start, end, position, nodes)
+	position += n.left.count()
+	if position >= start && position < end {
+		*nodes = append(*nodes, n)
+	}
+	n.right.collect(start, end, position+1, nodes)
+}
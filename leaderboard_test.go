@@ -0,0 +1,158 @@
+package elo_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+func TestLeaderboard(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	l := elo.NewLeaderboard()
+	players := []*player{{1500}, {1600}, {1400}, {1500}}
+	for _, p := range players {
+		l.Update(p)
+	}
+
+	tests := []struct {
+		p          *player
+		rank       int
+		dense      int
+		percentile float64
+	}{
+		{players[1], 1, 1, 100},
+		{players[0], 2, 2, 50},
+		{players[3], 2, 2, 50},
+		{players[2], 4, 3, 0},
+	}
+	for _, test := range tests {
+		e, err := l.Entry(test.p)
+		if err != nil || e.Rank != test.rank || e.DenseRank != test.dense {
+			t.Fail()
+			t.Logf("Expected rank %d and dense rank %d, got %d and %d\n", test.rank, test.dense, e.Rank, e.DenseRank)
+		}
+		if pct, _ := l.Percentile(test.p); !almostEqual(pct, test.percentile) {
+			t.Fail()
+			t.Logf("Expected percentile %f, got %f\n", test.percentile, pct)
+		}
+	}
+
+	// ties are kept in the order players reached the rating
+	top := l.Top(3)
+	if len(top) != 3 || top[0].Player != players[1] || top[1].Player != players[0] || top[2].Player != players[3] {
+		t.Fail()
+		t.Logf("Unexpected top 3 %+v\n", top)
+	}
+	n, err := l.Neighbors(players[3], 1)
+	if err != nil || len(n) != 3 || n[0].Player != players[0] || n[2].Player != players[2] {
+		t.Fail()
+		t.Logf("Unexpected neighbors %+v\n", n)
+	}
+
+	l.Play(c.NewMatch(players[2], players[1]), &elo.MatchResult{Outcome: elo.OutcomePlayerOneWin})
+	for _, p := range []*player{players[1], players[2]} {
+		if e, _ := l.Entry(p); e.Elo != p.elo {
+			t.Fail()
+			t.Logf("Expected elo %f, got %f\n", p.elo, e.Elo)
+		}
+	}
+	players[2].SetElo(1700)
+	l.Update(players[2])
+	if rank, _ := l.Rank(players[2]); rank != 1 {
+		t.Fail()
+		t.Logf("Expected rank %d, got %d\n", 1, rank)
+	}
+
+	if err := l.Remove(players[1]); err != nil || l.Len() != 3 {
+		t.Fail()
+		t.Log("Expected the player to be removed")
+	}
+	if _, err := l.Rank(players[1]); err != elo.ErrPlayerNotFound {
+		t.Fail()
+		t.Logf("Expected ErrPlayerNotFound, got %v\n", err)
+	}
+}
+
+func TestLeaderboardScale(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	l := elo.NewLeaderboard()
+	players := make([]*player, 200000)
+	for i := range players {
+		players[i] = &player{float64(1000 + rng.Intn(1000))}
+		l.Update(players[i])
+	}
+	for i := 0; i < 100000; i++ {
+		p := players[rng.Intn(len(players))]
+		p.SetElo(float64(1000 + rng.Intn(1000)))
+		l.Update(p)
+	}
+	if l.Len() != len(players) {
+		t.Fatalf("Expected %d players, got %d\n", len(players), l.Len())
+	}
+
+	ratings := make([]float64, len(players))
+	for i, p := range players {
+		ratings[i] = p.elo
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(ratings)))
+	for i := 0; i < 100; i++ {
+		p := players[rng.Intn(len(players))]
+		expected := sort.Search(len(ratings), func(j int) bool { return ratings[j] <= p.elo }) + 1
+		if rank, _ := l.Rank(p); rank != expected {
+			t.Fail()
+			t.Logf("Expected rank %d, got %d\n", expected, rank)
+		}
+	}
+	for i, e := range l.Range(100000, 1000) {
+		if e.Elo != ratings[100000+i] {
+			t.Fatalf("Expected elo %f at %d, got %f\n", ratings[100000+i], 100000+i, e.Elo)
+		}
+	}
+}
+
+func TestLeaderboardTies(t *testing.T) {
+	l := elo.NewLeaderboard()
+	players := make([]*player, 100000)
+	for i := range players {
+		players[i] = &player{1500}
+		l.Update(players[i])
+	}
+	// players move in and out of one large tie
+	for i := 0; i < 2000; i++ {
+		players[i].SetElo(1600)
+		l.Update(players[i])
+	}
+	for i := 0; i < 1000; i++ {
+		players[i].SetElo(1500)
+		l.Update(players[i])
+	}
+	if err := l.Remove(players[len(players)-1]); err != nil {
+		t.Fatal(err)
+	}
+
+	// 1000 players are at 1600 and 98999 at 1500
+	e, _ := l.Entry(players[5000])
+	if e.Rank != 1001 || e.DenseRank != 2 {
+		t.Fail()
+		t.Logf("Expected rank %d and dense rank %d, got %d and %d\n", 1001, 2, e.Rank, e.DenseRank)
+	}
+	if pct, _ := l.Percentile(players[1500]); !almostEqual(pct, 100*(98999+999.0/2)/99998) {
+		t.Fail()
+		t.Logf("Expected percentile %f, got %f\n", 100*(98999+999.0/2)/99998, pct)
+	}
+
+	// players who returned to 1500 are behind those who never left
+	n, err := l.Neighbors(players[0], 1)
+	if err != nil || len(n) != 3 || n[0].Player != players[99998] || n[2].Player != players[1] || n[1].Rank != 1001 || n[1].DenseRank != 2 ||
+		l.Len() != 99999 {
+		t.Fail()
+		t.Logf("Unexpected neighbors %+v\n", n)
+	}
+	if top := l.Range(999, 2); len(top) != 2 || top[0].Player != players[1999] || top[1].Player != players[2000] ||
+		top[1].Rank != 1001 || top[1].DenseRank != 2 {
+		t.Fail()
+		t.Logf("Unexpected range %+v\n", top)
+	}
+}
@@ -0,0 +1,254 @@
+package elo
+
+import (
+	"errors"
+	"path"
+	"reflect"
+	"runtime"
+)
+
+var (
+	ErrEntryNotFound = errors.New("elo: ledger entry not found")
+	ErrEntryReverted = errors.New("elo: ledger entry has already been reverted")
+	ErrRevertEntry   = errors.New("elo: a revert cannot be reverted")
+)
+
+// A snapshot of a player's rating. RatingDeviation and Volatility are only recorded
+// for players that implement GlickoPlayer.
+type LedgerRating struct {
+	Elo             float64
+	RatingDeviation float64
+	Volatility      float64
+}
+
+// A record of a single match applied through a Ledger.
+type LedgerEntry struct {
+	ID        int
+	PlayerOne Player
+	PlayerTwo Player
+	Result    MatchResult
+
+	// The strategy input the match was rated with, including every parameter.
+	Input CalculatorInput
+
+	// The name of the strategy function, i.e. "go-elo.StrategyDefault".
+	Strategy string
+
+	PlayerOneBefore LedgerRating
+	PlayerTwoBefore LedgerRating
+	PlayerOneAfter  LedgerRating
+	PlayerTwoAfter  LedgerRating
+
+	// The ID of the entry this entry reverts, or -1. A revert records the reverted match,
+	// with the players' ratings before and after it was undone.
+	Reverts int
+
+	// The ID of the entry this entry plays again after an earlier match was reverted,
+	// or -1.
+	Replays int
+
+	// Whether the match has been reverted, either itself or as played again in a later
+	// entry. Entries are never changed once recorded, so this is reported from the
+	// later entries.
+	Reverted bool
+
+	strategy StrategyFunc
+	kFactor  KFactorFunc
+}
+
+// An append-only record of every match played through it, which allows a mistaken
+// result to be reverted. Reverting a match records the revert, then plays every later
+// match again in order as new entries, so that their ratings are recomputed as if the
+// reverted match had never been played. Entries are never changed once recorded.
+type Ledger struct {
+	entries []*LedgerEntry
+
+	// the entries that have been reverted, and the entry each entry was played again as
+	reverted map[int]bool
+	replayed map[int]int
+}
+
+func NewLedger() *Ledger {
+	return new(Ledger)
+}
+
+// Plays the match and records it in the ledger. Returns the new entry, or nil if the
+// match was not played because it had already finished or was an ignored draw.
+func (l *Ledger) Play(m *Match, result *MatchResult) *LedgerEntry {
+	b1, b2 := snapshot(m.PlayerOne), snapshot(m.PlayerTwo)
+	input := m.play(result)
+	if input == nil {
+		return nil
+	}
+	e := &LedgerEntry{
+		ID:              len(l.entries),
+		PlayerOne:       m.PlayerOne,
+		PlayerTwo:       m.PlayerTwo,
+		Result:          *result,
+		Input:           *input,
+		Strategy:        strategyName(m.strategy),
+		PlayerOneBefore: b1,
+		PlayerTwoBefore: b2,
+		PlayerOneAfter:  snapshot(m.PlayerOne),
+		PlayerTwoAfter:  snapshot(m.PlayerTwo),
+		Reverts:         -1,
+		Replays:         -1,
+		strategy:        m.strategy,
+		kFactor:         m.kFactor,
+	}
+	l.entries = append(l.entries, e)
+	return l.copy(e)
+}
+
+// Returns a copy of every entry in the order they were recorded.
+func (l *Ledger) Entries() []LedgerEntry {
+	entries := make([]LedgerEntry, len(l.entries))
+	for i, e := range l.entries {
+		entries[i] = *l.copy(e)
+	}
+	return entries
+}
+
+// Returns a copy of the entry with the given ID.
+func (l *Ledger) Entry(id int) (LedgerEntry, error) {
+	if id < 0 || id >= len(l.entries) {
+		return LedgerEntry{}, ErrEntryNotFound
+	}
+	return *l.copy(l.entries[id]), nil
+}
+
+func (l *Ledger) copy(e *LedgerEntry) *LedgerEntry {
+	copied := *e
+	copied.Reverted = l.reverted[l.latest(e.ID)]
+	return &copied
+}
+
+// Returns the ID of the entry the match of the given entry was last played as.
+func (l *Ledger) latest(id int) int {
+	for {
+		next, ok := l.replayed[id]
+		if !ok {
+			return id
+		}
+		id = next
+	}
+}
+
+// Reverts the match of the entry with the given ID, as last played, then plays every
+// later match again in order, using the parameters it was originally played with and
+// each player's current K-Factor. The revert and the matches played again are appended
+// as new entries. Every player involved in the reverted or a later match is left with
+// their recomputed rating, overwriting any change made to them outside of the ledger
+// since.
+func (l *Ledger) Revert(id int) error {
+	if id < 0 || id >= len(l.entries) {
+		return ErrEntryNotFound
+	}
+	if l.reverted == nil {
+		l.reverted = make(map[int]bool)
+		l.replayed = make(map[int]int)
+	}
+	id = l.latest(id)
+	target := l.entries[id]
+	if target.Reverts >= 0 {
+		return ErrRevertEntry
+	}
+	if l.reverted[id] {
+		return ErrEntryReverted
+	}
+
+	// the matches after the reverted one that still stand
+	later := []*LedgerEntry{target}
+	for _, e := range l.entries[id+1:] {
+		if _, ok := l.replayed[e.ID]; !ok && e.Reverts < 0 && !l.reverted[e.ID] {
+			later = append(later, e)
+		}
+	}
+
+	// rewind every affected player to their rating before their first match
+	// at or after the reverted one
+	revert := *target
+	revert.ID = len(l.entries)
+	revert.Reverts = id
+	revert.Replays = -1
+	revert.PlayerOneBefore = snapshot(target.PlayerOne)
+	revert.PlayerTwoBefore = snapshot(target.PlayerTwo)
+	seen := make(map[Player]bool)
+	for _, e := range later {
+		if !seen[e.PlayerOne] {
+			seen[e.PlayerOne] = true
+			restore(e.PlayerOne, e.PlayerOneBefore)
+		}
+		if !seen[e.PlayerTwo] {
+			seen[e.PlayerTwo] = true
+			restore(e.PlayerTwo, e.PlayerTwoBefore)
+		}
+	}
+	revert.PlayerOneAfter = snapshot(target.PlayerOne)
+	revert.PlayerTwoAfter = snapshot(target.PlayerTwo)
+	l.entries = append(l.entries, &revert)
+	l.reverted[id] = true
+
+	for _, e := range later[1:] {
+		l.replay(e)
+	}
+	return nil
+}
+
+// Plays the entry's match again against the players' current ratings, and appends it as
+// a new entry.
+func (l *Ledger) replay(e *LedgerEntry) {
+	m := &Match{PlayerOne: e.PlayerOne, PlayerTwo: e.PlayerTwo, kFactor: e.kFactor}
+	input := m.input(&e.Result)
+	played := e.Input
+	played.PlayerOne = input.PlayerOne
+	played.PlayerTwo = input.PlayerTwo
+	played.PlayerOneK = input.PlayerOneK
+	played.PlayerTwoK = input.PlayerTwoK
+	played.PlayerOneRatingDeviation = input.PlayerOneRatingDeviation
+	played.PlayerTwoRatingDeviation = input.PlayerTwoRatingDeviation
+	played.PlayerOneVolatility = input.PlayerOneVolatility
+	played.PlayerTwoVolatility = input.PlayerTwoVolatility
+	played.PlayerOneIdlePeriods = input.PlayerOneIdlePeriods
+	played.PlayerTwoIdlePeriods = input.PlayerTwoIdlePeriods
+
+	r := *e
+	r.ID = len(l.entries)
+	r.Input = played
+	r.Reverts = -1
+	r.Replays = e.ID
+	r.PlayerOneBefore = snapshot(e.PlayerOne)
+	r.PlayerTwoBefore = snapshot(e.PlayerTwo)
+	n1, n2 := e.strategy(&played)
+	m.apply(&played, n1, n2)
+	r.PlayerOneAfter = snapshot(e.PlayerOne)
+	r.PlayerTwoAfter = snapshot(e.PlayerTwo)
+
+	l.entries = append(l.entries, &r)
+	l.replayed[e.ID] = r.ID
+}
+
+func snapshot(p Player) LedgerRating {
+	r := LedgerRating{Elo: p.GetElo()}
+	if g, ok := p.(GlickoPlayer); ok {
+		r.RatingDeviation = g.GetRatingDeviation()
+		r.Volatility = g.GetVolatility()
+	}
+	return r
+}
+
+func restore(p Player, r LedgerRating) {
+	p.SetElo(r.Elo)
+	if g, ok := p.(GlickoPlayer); ok {
+		g.SetRatingDeviation(r.RatingDeviation)
+		g.SetVolatility(r.Volatility)
+	}
+}
+
+func strategyName(sf StrategyFunc) string {
+	f := runtime.FuncForPC(reflect.ValueOf(sf).Pointer())
+	if f == nil {
+		return ""
+	}
+	return path.Base(f.Name())
+}
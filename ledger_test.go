@@ -0,0 +1,170 @@
+package elo_test
+
+import (
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+func TestLedger(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	l := elo.NewLedger()
+
+	a, b := &player{1600}, &player{1800}
+
+	e := l.Play(c.NewMatch(a, b), &elo.MatchResult{
+		Outcome: elo.OutcomePlayerTwoWin,
+	})
+	if e == nil {
+		t.Fatal("Expected a ledger entry")
+	}
+	if e.ID != 0 || e.Strategy != "go-elo.StrategyDefault" {
+		t.Fail()
+		t.Logf("Unexpected entry %d played with %s\n", e.ID, e.Strategy)
+	}
+	if !almostEqual(e.PlayerOneBefore.Elo, 1600) || !almostEqual(e.PlayerOneAfter.Elo, 1592.311902) {
+		t.Fail()
+		t.Logf("Expected P1 from %f to %f, got %f to %f\n", 1600.0, 1592.311902,
+			e.PlayerOneBefore.Elo, e.PlayerOneAfter.Elo)
+	}
+	if !almostEqual(e.Input.K, 32) || !almostEqual(e.Input.Deviation, 400) {
+		t.Fail()
+		t.Logf("Expected parameters to be recorded, got %+v\n", e.Input)
+	}
+
+	// finished matches and ignored draws are not recorded
+	m := c.NewMatch(a, b)
+	m.Play(&elo.MatchResult{})
+	if l.Play(m, &elo.MatchResult{}) != nil || len(l.Entries()) != 1 {
+		t.Fail()
+		t.Log("A finished match must not be recorded")
+	}
+}
+
+func TestLedgerRevert(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	l := elo.NewLedger()
+
+	a, b, d := &player{1500}, &player{1600}, &player{1700}
+
+	l.Play(c.NewMatch(a, b), &elo.MatchResult{Outcome: elo.OutcomePlayerOneWin})
+	l.Play(c.NewMatch(b, d), &elo.MatchResult{Outcome: elo.OutcomePlayerOneWin})
+	m := c.NewMatch(a, d)
+	m.SetKValue(16)
+	l.Play(m, &elo.MatchResult{Outcome: elo.OutcomeDraw})
+
+	if err := l.Revert(0); err != nil {
+		t.Fatal(err)
+	}
+
+	// the same matches, without the first
+	ea, eb, ed := &player{1500}, &player{1600}, &player{1700}
+	c.NewMatch(eb, ed).Play(&elo.MatchResult{Outcome: elo.OutcomePlayerOneWin})
+	m = c.NewMatch(ea, ed)
+	m.SetKValue(16)
+	m.Play(&elo.MatchResult{Outcome: elo.OutcomeDraw})
+
+	if !almostEqual(a.elo, ea.elo) || !almostEqual(b.elo, eb.elo) || !almostEqual(d.elo, ed.elo) {
+		t.Fail()
+		t.Logf("Expected %f, %f, %f after revert, got %f, %f, %f\n",
+			ea.elo, eb.elo, ed.elo, a.elo, b.elo, d.elo)
+	}
+
+	e, err := l.Entry(0)
+	if err != nil || !e.Reverted {
+		t.Fail()
+		t.Log("Expected entry to be marked as reverted")
+	}
+	// the revert and the replayed matches are appended, and earlier entries are unchanged
+	e, _ = l.Entry(2)
+	if e.Reverted || e.Replays != -1 || !almostEqual(e.PlayerOneBefore.Elo, 1520.482080) {
+		t.Fail()
+		t.Logf("Expected the original entry to be unchanged, got %+v\n", e)
+	}
+	if e, _ = l.Entry(3); e.Reverts != 0 || !almostEqual(e.PlayerOneAfter.Elo, 1500) {
+		t.Fail()
+		t.Logf("Expected a revert of entry 0, got %+v\n", e)
+	}
+	e, _ = l.Entry(5)
+	if e.Replays != 2 || !almostEqual(e.PlayerTwoAfter.Elo, ed.elo) || !almostEqual(e.Input.K, 16) {
+		t.Fail()
+		t.Logf("Expected replayed entry to end at %f with K %f, got %f with K %f\n",
+			ed.elo, 16.0, e.PlayerTwoAfter.Elo, e.Input.K)
+	}
+	if len(l.Entries()) != 6 {
+		t.Fail()
+		t.Logf("Expected %d entries, got %d\n", 6, len(l.Entries()))
+	}
+
+	if err := l.Revert(0); err != elo.ErrEntryReverted {
+		t.Fail()
+		t.Logf("Expected %v, got %v\n", elo.ErrEntryReverted, err)
+	}
+	if err := l.Revert(3); err != elo.ErrRevertEntry {
+		t.Fail()
+		t.Logf("Expected %v, got %v\n", elo.ErrRevertEntry, err)
+	}
+	if err := l.Revert(6); err != elo.ErrEntryNotFound {
+		t.Fail()
+		t.Logf("Expected %v, got %v\n", elo.ErrEntryNotFound, err)
+	}
+	if _, err := l.Entry(-1); err != elo.ErrEntryNotFound {
+		t.Fail()
+		t.Logf("Expected %v, got %v\n", elo.ErrEntryNotFound, err)
+	}
+
+	// reverting everything restores the original ratings, where the original entries
+	// revert the matches as they were played again
+	l.Revert(1)
+	l.Revert(2)
+	if !almostEqual(a.elo, 1500) || !almostEqual(b.elo, 1600) || !almostEqual(d.elo, 1700) {
+		t.Fail()
+		t.Logf("Expected original ratings, got %f, %f, %f\n", a.elo, b.elo, d.elo)
+	}
+}
+
+func TestLedgerRevertGlicko(t *testing.T) {
+	c := elo.NewCalculatorBuilder().
+		WithStrategy(elo.StrategyGlicko2).
+		Build()
+	l := elo.NewLedger()
+
+	a := &glickoPlayer{player{1500}, 200, 0.06}
+	b := &glickoPlayer{player{1400}, 30, 0.06}
+
+	l.Play(c.NewMatch(a, b), &elo.MatchResult{Outcome: elo.OutcomePlayerOneWin})
+	if err := l.Revert(0); err != nil {
+		t.Fatal(err)
+	}
+
+	if !almostEqual(a.elo, 1500) || !almostEqual(a.rd, 200) || !almostEqual(a.vol, 0.06) {
+		t.Fail()
+		t.Logf("Expected P1 to be restored, got %f, %f, %f\n", a.elo, a.rd, a.vol)
+	}
+}
+
+func TestLedgerRevertKFactor(t *testing.T) {
+	c := elo.NewCalculatorBuilder().WithKFactor(elo.KFactorFIDE).Build()
+	l := elo.NewLedger()
+
+	a := &experiencedPlayer{player: player{1500}, games: 0, peak: 1500}
+	b, d := &player{1500}, &player{1500}
+	l.Play(c.NewMatch(a, b), &elo.MatchResult{Outcome: elo.OutcomePlayerOneWin})
+	l.Play(c.NewMatch(a, d), &elo.MatchResult{Outcome: elo.OutcomePlayerOneWin})
+
+	// by the time of the revert, a has played enough games for K 20
+	a.games = 30
+	if err := l.Revert(0); err != nil {
+		t.Fatal(err)
+	}
+	e, _ := l.Entry(3)
+	if e.Replays != 1 || !almostEqual(e.Input.PlayerOneK, 20) || !almostEqual(a.elo, 1510) {
+		t.Fail()
+		t.Logf("Expected the replayed match to use K %f and end at %f, got %f and %f\n", 20.0, 1510.0,
+			e.Input.PlayerOneK, a.elo)
+	}
+	if e, _ = l.Entry(1); !almostEqual(e.Input.PlayerOneK, 40) {
+		t.Fail()
+		t.Logf("Expected the original entry to keep K %f, got %f\n", 40.0, e.Input.PlayerOneK)
+	}
+}
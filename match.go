@@ -1,9 +1,5 @@
 package elo
 
-import (
-	"math"
-)
-
 type Player interface {
 	GetElo() float64
 	SetElo(float64)
@@ -18,6 +14,15 @@ type Match struct {
 	deviation   float64
 	scoreWeight float64
 	ignoreDraws bool
+	tau         float64
+	glickoC     float64
+	kFactor     KFactorFunc
+
+	drawParameter float64
+
+	autocorrelation float64
+
+	advantage float64
 }
 
 // Args p1 and p2 should be non-nil pointers.
@@ -30,6 +35,12 @@ func (c *Calculator) NewMatch(p1, p2 Player) *Match {
 	m.deviation = c.deviation
 	m.scoreWeight = c.scoreWeight
 	m.ignoreDraws = c.ignoreDraws
+	m.tau = c.tau
+	m.glickoC = c.glickoC
+	m.kFactor = c.kFactor
+	m.drawParameter = c.drawParameter
+	m.autocorrelation = c.autocorrelation
+	m.advantage = c.advantage
 	return m
 }
 
@@ -38,6 +49,9 @@ func (c *Calculator) NewMatch(p1, p2 Player) *Match {
 type MatchOdds struct {
 	PlayerOneOdds float64
 	PlayerTwoOdds float64
+
+	// The odds of a draw. Always 0 unless the calculator has a draw parameter.
+	DrawOdds float64
 }
 
 type MatchResult struct {
@@ -67,11 +81,13 @@ func (m *Match) SetStrategy(sf StrategyFunc) {
 }
 
 // K must be non-negative. If a negative value is provided, K will be unchanged.
+// Setting K for a match overrides the calculator's KFactorFunc.
 func (m *Match) SetKValue(k float64) {
 	if k < 0 {
 		return
 	}
 	m.k = k
+	m.kFactor = nil
 }
 func (m *Match) GetKValue() float64 {
 	return m.k
@@ -107,16 +123,29 @@ func (m *Match) GetIgnoreDraws() bool {
 	return m.ignoreDraws
 }
 
-func (m Match) GetOdds() *MatchOdds {
-	R1 := math.Pow(10, m.PlayerOne.GetElo()/m.deviation)
-	R2 := math.Pow(10, m.PlayerTwo.GetElo()/m.deviation)
-
-	E1 := R1 / (R1 + R2)
-	E2 := R2 / (R1 + R2)
-	return &MatchOdds{
-		PlayerOneOdds: E1,
-		PlayerTwoOdds: E2,
+// The draw parameter must be non-negative.
+// If a negative value is provided, the draw parameter will be unchanged.
+func (m *Match) SetDrawParameter(nu float64) {
+	if nu < 0 {
+		return
 	}
+	m.drawParameter = nu
+}
+func (m *Match) GetDrawParameter() float64 {
+	return m.drawParameter
+}
+
+// Set an advantage in elo for player one, such as home field or moving first, to be
+// used for this match only. A negative value favors player two.
+func (m *Match) SetAdvantage(a float64) {
+	m.advantage = a
+}
+func (m *Match) GetAdvantage() float64 {
+	return m.advantage
+}
+
+func (m Match) GetOdds() *MatchOdds {
+	return davidsonOdds(m.PlayerOne.GetElo()+m.advantage, m.PlayerTwo.GetElo(), m.deviation, m.drawParameter)
 }
 
 // Adjusts the Match's player's elo according to who won the match.
@@ -126,63 +155,97 @@ func (m Match) GetOdds() *MatchOdds {
 // Note: Play() uses a reference to the Match's calculator to determine the new elos. If the
 // calculator no longer exists, the function will panic.
 func (m *Match) Play(result *MatchResult) {
+	m.play(result)
+}
+
+// Plays the match, returning a copy of the strategy input as it was before the strategy
+// ran, or nil if the match was not played.
+func (m *Match) play(result *MatchResult) *CalculatorInput {
 	if m.finished ||
 		((result.Outcome == OutcomeDraw) &&
 			m.ignoreDraws &&
 			(result.PlayerOneScore == result.PlayerTwoScore)) {
-		return
+		return nil
 	}
-	n1, n2 := m.strategy(&CalculatorInput{
-		PlayerOne:      m.PlayerOne.GetElo(),
-		PlayerTwo:      m.PlayerTwo.GetElo(),
-		PlayerOneScore: result.PlayerOneScore,
-		PlayerTwoScore: result.PlayerTwoScore,
-		Outcome:        result.Outcome,
-		Deviation:      m.deviation,
-		ScoreWeight:    m.scoreWeight,
-		K:              m.k,
-	})
-	// fmt.Printf("%+v", CalculatorInput{
-	// 	PlayerOne:      m.PlayerOne.GetElo(),
-	// 	PlayerTwo:      m.PlayerTwo.GetElo(),
-	// 	PlayerOneScore: result.PlayerOneScore,
-	// 	PlayerTwoScore: result.PlayerTwoScore,
-	// 	Outcome:        result.Outcome,
-	// 	Deviation:      m.deviation,
-	// 	ScoreWeight:    m.scoreWeight,
-	// 	K:              m.k,
-	// })
-	m.PlayerOne.SetElo(n1)
-	m.PlayerTwo.SetElo(n2)
+	input := m.input(result)
+	played := *input
+	n1, n2 := m.strategy(input)
+	m.apply(input, n1, n2)
 	m.finished = true
+	return &played
 }
 
 // Returns how much player one stands to gain if they win.
-// Equivalent to how much player two will lose if they lose.
+// Equivalent to how much player two will lose if they lose, unless the players
+// have different K-Values.
 //
 // Note: May not be accurate when using a scored strategy.
 func (m Match) PlayerOneGain() float64 {
-	n1, _ := m.strategy(&CalculatorInput{
-		PlayerOne: m.PlayerOne.GetElo(),
-		PlayerTwo: m.PlayerTwo.GetElo(),
-		Outcome:   OutcomePlayerOneWin,
-		K:         m.k,
-		Deviation: m.deviation,
-	})
+	n1, _ := m.strategy(m.input(&MatchResult{
+		Outcome: OutcomePlayerOneWin,
+	}))
 	return n1 - m.PlayerOne.GetElo()
 }
 
 // Returns how much player two stands to gain if they win.
-// Equivalent to how much player one will lose if they lose.
+// Equivalent to how much player one will lose if they lose, unless the players
+// have different K-Values.
 //
 // Note: May not be accurate when using a scored strategy.
 func (m Match) PlayerTwoGain() float64 {
-	_, n2 := m.strategy(&CalculatorInput{
-		PlayerOne: m.PlayerOne.GetElo(),
-		PlayerTwo: m.PlayerTwo.GetElo(),
-		Outcome:   OutcomePlayerTwoWin,
-		K:         m.k,
-		Deviation: m.deviation,
-	})
+	_, n2 := m.strategy(m.input(&MatchResult{
+		Outcome: OutcomePlayerTwoWin,
+	}))
 	return n2 - m.PlayerTwo.GetElo()
 }
+
+// Builds the strategy input for the match using the players' current ratings.
+func (m *Match) input(result *MatchResult) *CalculatorInput {
+	input := &CalculatorInput{
+		PlayerOne:       m.PlayerOne.GetElo(),
+		PlayerTwo:       m.PlayerTwo.GetElo(),
+		PlayerOneScore:  result.PlayerOneScore,
+		PlayerTwoScore:  result.PlayerTwoScore,
+		Outcome:         result.Outcome,
+		Deviation:       m.deviation,
+		ScoreWeight:     m.scoreWeight,
+		K:               m.k,
+		Tau:             m.tau,
+		C:               m.glickoC,
+		DrawParameter:   m.drawParameter,
+		Autocorrelation: m.autocorrelation,
+		Advantage:       m.advantage,
+		PlayerOneK:      playerK(m.kFactor, m.PlayerOne),
+		PlayerTwoK:      playerK(m.kFactor, m.PlayerTwo),
+	}
+	if g, ok := m.PlayerOne.(GlickoPlayer); ok {
+		input.PlayerOneRatingDeviation = g.GetRatingDeviation()
+		input.PlayerOneVolatility = g.GetVolatility()
+	}
+	if g, ok := m.PlayerTwo.(GlickoPlayer); ok {
+		input.PlayerTwoRatingDeviation = g.GetRatingDeviation()
+		input.PlayerTwoVolatility = g.GetVolatility()
+	}
+	if p, ok := m.PlayerOne.(IdlePlayer); ok {
+		input.PlayerOneIdlePeriods = p.GetIdlePeriods()
+	}
+	if p, ok := m.PlayerTwo.(IdlePlayer); ok {
+		input.PlayerTwoIdlePeriods = p.GetIdlePeriods()
+	}
+	return input
+}
+
+// Stores the new ratings, along with any rating deviation and volatility the
+// strategy wrote back into the input.
+func (m *Match) apply(input *CalculatorInput, n1, n2 float64) {
+	m.PlayerOne.SetElo(n1)
+	m.PlayerTwo.SetElo(n2)
+	if g, ok := m.PlayerOne.(GlickoPlayer); ok && input.PlayerOneRatingDeviation > 0 {
+		g.SetRatingDeviation(input.PlayerOneRatingDeviation)
+		g.SetVolatility(input.PlayerOneVolatility)
+	}
+	if g, ok := m.PlayerTwo.(GlickoPlayer); ok && input.PlayerTwoRatingDeviation > 0 {
+		g.SetRatingDeviation(input.PlayerTwoRatingDeviation)
+		g.SetVolatility(input.PlayerTwoVolatility)
+	}
+}
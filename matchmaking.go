@@ -0,0 +1,286 @@
+package elo
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrAlreadyQueued = errors.New("elo: the player is already in the queue")
+	ErrNotQueued     = errors.New("elo: the player is not in the queue")
+)
+
+// A match formed by a Matchmaker. With a team size of 1, each team has a single player,
+// and the lobby can be played with NewMatch(l.TeamOne[0], l.TeamTwo[0]).
+type Lobby struct {
+	TeamOne []Player
+	TeamTwo []Player
+
+	// The quality of the match from Match.Quality, or TeamMatch.Quality for teams.
+	Quality float64
+
+	// How long the player who waited longest had been in the queue.
+	Wait time.Duration
+}
+
+// Forms matches between waiting players. Each player will accept opponents and
+// teammates within a rating gap that starts at the initial gap and widens as they wait,
+// up to the maximum gap. A match can be formed when the difference between the highest
+// and lowest rated players in it is within the gap of any of its players, so a player
+// who has waited long can be matched with players who just joined.
+//
+// Players who have waited longest are matched first, each with the closest rated players
+// still in the queue, split into the two teams that make the match with the highest
+// quality. Ratings are read each time matches are formed. A Matchmaker is safe for
+// concurrent use.
+type Matchmaker struct {
+	c          Calculator
+	teamSize   int
+	initialGap float64
+	gapGrowth  float64
+	maxGap     float64
+
+	mu    sync.Mutex
+	queue []queueEntry
+}
+
+type queueEntry struct {
+	player Player
+	joined time.Time
+}
+
+// By default, players are matched one against one, with a gap of 100 elo that widens by
+// 10 elo for every second they wait, without a limit.
+func (c *Calculator) NewMatchmaker() *Matchmaker {
+	return &Matchmaker{
+		c:          *c,
+		teamSize:   1,
+		initialGap: 100,
+		gapGrowth:  10,
+		maxGap:     math.Inf(1),
+	}
+}
+
+// The number of players on each team. Team size must be greater than 0. If a
+// non-positive value is provided, the team size will be unchanged.
+func (m *Matchmaker) SetTeamSize(n int) {
+	if n <= 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.teamSize = n
+}
+
+// The rating gap a player accepts as soon as they join. The gap must be non-negative.
+// If a negative value is provided, the gap will be unchanged.
+func (m *Matchmaker) SetInitialGap(g float64) {
+	if g < 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.initialGap = g
+}
+
+// How much the rating gap widens for every second a player waits. Growth must be
+// non-negative. If a negative value is provided, the growth will be unchanged.
+func (m *Matchmaker) SetGapGrowth(g float64) {
+	if g < 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gapGrowth = g
+}
+
+// The widest rating gap a player accepts, however long they wait. The gap must be
+// non-negative. If a negative value is provided, the gap will be unchanged.
+func (m *Matchmaker) SetMaxGap(g float64) {
+	if g < 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.maxGap = g
+}
+
+// Returns the rating gap a player accepts after waiting for the given time.
+func (m *Matchmaker) Gap(wait time.Duration) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.gap(wait)
+}
+
+func (m *Matchmaker) gap(wait time.Duration) float64 {
+	return math.Min(m.initialGap+m.gapGrowth*math.Max(wait.Seconds(), 0), m.maxGap)
+}
+
+// Adds a player to the queue, who has been waiting since joined. Args p should be a
+// non-nil pointer.
+func (m *Matchmaker) Join(p Player, joined time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range m.queue {
+		if e.player == p {
+			return ErrAlreadyQueued
+		}
+	}
+	m.queue = append(m.queue, queueEntry{player: p, joined: joined})
+	return nil
+}
+
+// Removes a player from the queue.
+func (m *Matchmaker) Leave(p Player) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, e := range m.queue {
+		if e.player == p {
+			m.queue = append(m.queue[:i], m.queue[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotQueued
+}
+
+// Returns the number of players in the queue.
+func (m *Matchmaker) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.queue)
+}
+
+// Forms as many matches as the queue allows at the given time, and removes their players
+// from the queue. Matches are returned in the order they were formed, starting with the
+// match of the player who waited longest.
+func (m *Matchmaker) Match(now time.Time) []Lobby {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := len(m.queue)
+	size := 2 * m.teamSize
+	elo := make([]float64, n)
+	gaps := make([]float64, n)
+	for i, e := range m.queue {
+		elo[i] = e.player.GetElo()
+		gaps[i] = m.gap(now.Sub(e.joined))
+	}
+	// players ordered by rating, so the closest rated players are neighbors
+	byRating := make([]int, n)
+	for i := range byRating {
+		byRating[i] = i
+	}
+	sort.SliceStable(byRating, func(a, b int) bool {
+		return elo[byRating[a]] < elo[byRating[b]]
+	})
+	position := make([]int, n)
+	for pos, i := range byRating {
+		position[i] = pos
+	}
+	byWait := make([]int, n)
+	for i := range byWait {
+		byWait[i] = i
+	}
+	sort.SliceStable(byWait, func(a, b int) bool {
+		return m.queue[byWait[a]].joined.Before(m.queue[byWait[b]].joined)
+	})
+
+	taken := make([]bool, n)
+	var lobbies []Lobby
+	for _, anchor := range byWait {
+		if taken[anchor] {
+			continue
+		}
+		// the players still in the queue closest in rating on either side
+		var left, right []int
+		for pos := position[anchor] - 1; pos >= 0 && len(left) < size-1; pos-- {
+			if !taken[byRating[pos]] {
+				left = append(left, byRating[pos])
+			}
+		}
+		for pos := position[anchor] + 1; pos < n && len(right) < size-1; pos++ {
+			if !taken[byRating[pos]] {
+				right = append(right, byRating[pos])
+			}
+		}
+		around := make([]int, 0, len(left)+len(right)+1)
+		for i := len(left) - 1; i >= 0; i-- {
+			around = append(around, left[i])
+		}
+		around = append(around, anchor)
+		around = append(around, right...)
+
+		// every run of neighbors that includes the anchor and fits within a gap
+		var best []int
+		var bestLobby Lobby
+		for start := max(len(left)-size+1, 0); start <= len(left) && start+size <= len(around); start++ {
+			run := around[start : start+size]
+			widest := 0.0
+			for _, i := range run {
+				widest = math.Max(widest, gaps[i])
+			}
+			if elo[run[size-1]]-elo[run[0]] > widest {
+				continue
+			}
+			players := make([]Player, size)
+			for j, i := range run {
+				players[j] = m.queue[i].player
+			}
+			if lobby := m.split(players); best == nil || lobby.Quality > bestLobby.Quality {
+				best, bestLobby = run, lobby
+			}
+		}
+		if best == nil {
+			continue
+		}
+		for _, i := range best {
+			taken[i] = true
+			bestLobby.Wait = max(bestLobby.Wait, now.Sub(m.queue[i].joined))
+		}
+		lobbies = append(lobbies, bestLobby)
+	}
+
+	queue := m.queue[:0]
+	for i, e := range m.queue {
+		if !taken[i] {
+			queue = append(queue, e)
+		}
+	}
+	clear(m.queue[len(queue):])
+	m.queue = queue
+	return lobbies
+}
+
+// Returns the split of the players into two teams with the highest quality, trying
+// every split.
+func (m *Matchmaker) split(players []Player) Lobby {
+	var best Lobby
+	best.Quality = -1
+	for mask := uint(0); mask < 1<<len(players); mask++ {
+		if bits.OnesCount(mask) != m.teamSize {
+			continue
+		}
+		var t1, t2 []Player
+		for i, p := range players {
+			if mask&(1<<i) != 0 {
+				t1 = append(t1, p)
+			} else {
+				t2 = append(t2, p)
+			}
+		}
+		var q float64
+		if m.teamSize == 1 {
+			q = m.c.NewMatch(t1[0], t2[0]).Quality()
+		} else {
+			q = m.c.NewTeamMatch(t1, t2).Quality()
+		}
+		if q > best.Quality {
+			best = Lobby{TeamOne: t1, TeamTwo: t2, Quality: q}
+		}
+	}
+	return best
+}
@@ -0,0 +1,111 @@
+package elo_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gabehf/go-elo"
+)
+
+func TestMatchmaker(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	m := c.NewMatchmaker()
+	start := time.Unix(0, 0)
+
+	p1, p2, p3, p4 := &player{1510}, &player{1500}, &player{1600}, &player{1610}
+	m.Join(p1, start)
+	m.Join(p2, start.Add(time.Second))
+	m.Join(p3, start.Add(time.Second))
+	m.Join(p4, start.Add(time.Second))
+	if err := m.Join(p1, start); err != elo.ErrAlreadyQueued {
+		t.Fail()
+		t.Logf("Expected ErrAlreadyQueued, got %v\n", err)
+	}
+
+	// the longest waiting player gets the closest opponent
+	lobbies := m.Match(start.Add(2 * time.Second))
+	if len(lobbies) != 2 || m.Len() != 0 {
+		t.Fatalf("Expected 2 matches, got %d with %d players left\n", len(lobbies), m.Len())
+	}
+	l := lobbies[0]
+	if !((l.TeamOne[0] == p1 && l.TeamTwo[0] == p2) || (l.TeamOne[0] == p2 && l.TeamTwo[0] == p1)) {
+		t.Fail()
+		t.Log("Expected the first two players to be matched")
+	}
+	odds := c.NewMatch(l.TeamOne[0], l.TeamTwo[0]).GetOdds()
+	if q := 1 - math.Abs(odds.PlayerOneOdds-odds.PlayerTwoOdds); !almostEqual(l.Quality, q) {
+		t.Fail()
+		t.Logf("Expected quality %f, got %f\n", q, l.Quality)
+	}
+	if l.Wait != 2*time.Second {
+		t.Fail()
+		t.Logf("Expected a wait of %v, got %v\n", 2*time.Second, l.Wait)
+	}
+	if err := m.Leave(p1); err != elo.ErrNotQueued {
+		t.Fail()
+		t.Logf("Expected ErrNotQueued, got %v\n", err)
+	}
+}
+
+func TestMatchmakerGap(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	m := c.NewMatchmaker()
+	start := time.Unix(0, 0)
+	m.Join(&player{1500}, start)
+	m.Join(&player{1800}, start.Add(15*time.Second))
+
+	// 100 + 10 elo a second for the first player
+	if g := m.Gap(15 * time.Second); !almostEqual(g, 250) {
+		t.Fail()
+		t.Logf("Expected a gap of %f, got %f\n", 250.0, g)
+	}
+	if lobbies := m.Match(start.Add(15 * time.Second)); len(lobbies) != 0 {
+		t.Fail()
+		t.Log("Expected no match within the gap")
+	}
+	lobbies := m.Match(start.Add(20 * time.Second))
+	if len(lobbies) != 1 || lobbies[0].Wait != 20*time.Second {
+		t.Fail()
+		t.Logf("Expected a match once the gap reached 300, got %+v\n", lobbies)
+	}
+
+	m.SetMaxGap(150)
+	m.Join(&player{1500}, start)
+	m.Join(&player{1800}, start)
+	if lobbies := m.Match(start.Add(time.Hour)); len(lobbies) != 0 || m.Len() != 2 {
+		t.Fail()
+		t.Log("Expected the maximum gap to keep players apart")
+	}
+}
+
+func TestMatchmakerTeams(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	m := c.NewMatchmaker()
+	m.SetTeamSize(2)
+	m.SetInitialGap(400)
+	now := time.Unix(0, 0)
+
+	players := []*player{{1000}, {1100}, {1200}, {1300}, {2000}}
+	for _, p := range players {
+		m.Join(p, now)
+	}
+	lobbies := m.Match(now)
+	if len(lobbies) != 1 || m.Len() != 1 {
+		t.Fatalf("Expected 1 match, got %d\n", len(lobbies))
+	}
+	// 1000 and 1300 against 1100 and 1200 is even
+	l := lobbies[0]
+	if !almostEqual(l.Quality, 1) || len(l.TeamOne) != 2 || len(l.TeamTwo) != 2 {
+		t.Fail()
+		t.Logf("Expected quality %f, got %f\n", 1.0, l.Quality)
+	}
+	var sum float64
+	for _, p := range l.TeamOne {
+		sum += p.GetElo()
+	}
+	if !almostEqual(sum, 2300) {
+		t.Fail()
+		t.Logf("Expected team one to total %f, got %f\n", 2300.0, sum)
+	}
+}
@@ -0,0 +1,112 @@
+package elo
+
+import (
+	"errors"
+)
+
+var ErrPlacementMismatch = errors.New("elo: number of placements must match number of players")
+
+// A free-for-all match between any number of players. The result is rated as if every
+// player had played a separate match against every other player, with K divided by the
+// number of opponents so that a player's total change stays on the same scale as a
+// single match.
+type MultiMatch struct {
+	Players  []Player
+	finished bool
+	c        Calculator
+}
+
+// Args players should be non-nil pointers.
+func (c *Calculator) NewMultiMatch(players ...Player) *MultiMatch {
+	m := new(MultiMatch)
+	m.Players = players
+	m.c = *c
+	return m
+}
+
+// Set a strategy to be used for this match only.
+func (m *MultiMatch) SetStrategy(sf StrategyFunc) {
+	m.c.strategy = sf
+}
+
+// K must be non-negative. If a negative value is provided, K will be unchanged.
+// Setting K for a match overrides the calculator's KFactorFunc.
+func (m *MultiMatch) SetKValue(k float64) {
+	if k < 0 {
+		return
+	}
+	m.c.k = k
+	m.c.kFactor = nil
+}
+func (m *MultiMatch) GetKValue() float64 {
+	return m.c.k
+}
+
+// Adjusts every player's elo according to their finishing placement. placements[i] is
+// the finishing position of Players[i], where a lower placement is better and equal
+// placements are a tie. All pairings are rated using the ratings from before the match,
+// so the order of players does not matter. Scored strategies see each pairing as a 1-0
+// win or a 1-1 tie, and players with their own K-Value from the calculator's KFactorFunc
+// have it divided by the number of opponents in the same way.
+// Can only be called once. Any subsequent calls on the same match will result in no changes
+// to the players' elo ratings.
+//
+// Note: Only elo ratings are updated. Rating deviations and volatilities are unchanged.
+func (m *MultiMatch) Play(placements []int) error {
+	if len(placements) != len(m.Players) {
+		return ErrPlacementMismatch
+	}
+	if m.finished || len(m.Players) < 2 {
+		return nil
+	}
+
+	ratings := make([]float64, len(m.Players))
+	for i, p := range m.Players {
+		ratings[i] = p.GetElo()
+	}
+	deltas := make([]float64, len(m.Players))
+	opponents := float64(len(m.Players) - 1)
+	k := m.c.k / opponents
+	kValues := make([]float64, len(m.Players))
+	for i, p := range m.Players {
+		kValues[i] = playerK(m.c.kFactor, p) / opponents
+	}
+
+	for i := range m.Players {
+		for j := i + 1; j < len(m.Players); j++ {
+			// scored strategies see a win as 1-0 and a tie as 1-1
+			outcome, s1, s2 := OutcomeDraw, 1, 1
+			if placements[i] < placements[j] {
+				outcome, s2 = OutcomePlayerOneWin, 0
+			} else if placements[i] > placements[j] {
+				outcome, s1 = OutcomePlayerTwoWin, 0
+			} else if m.c.ignoreDraws {
+				continue
+			}
+			n1, n2 := m.c.strategy(&CalculatorInput{
+				PlayerOne:       ratings[i],
+				PlayerTwo:       ratings[j],
+				Outcome:         outcome,
+				PlayerOneScore:  s1,
+				PlayerTwoScore:  s2,
+				K:               k,
+				PlayerOneK:      kValues[i],
+				PlayerTwoK:      kValues[j],
+				Deviation:       m.c.deviation,
+				ScoreWeight:     m.c.scoreWeight,
+				Tau:             m.c.tau,
+				C:               m.c.glickoC,
+				DrawParameter:   m.c.drawParameter,
+				Autocorrelation: m.c.autocorrelation,
+			})
+			deltas[i] += n1 - ratings[i]
+			deltas[j] += n2 - ratings[j]
+		}
+	}
+
+	for i, p := range m.Players {
+		p.SetElo(ratings[i] + deltas[i])
+	}
+	m.finished = true
+	return nil
+}
@@ -0,0 +1,150 @@
+package elo_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+func TestMultiMatch(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+
+	p1 := &player{1500}
+	p2 := &player{1500}
+	p3 := &player{1500}
+
+	m := c.NewMultiMatch(p1, p2, p3)
+	if err := m.Play([]int{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !almostEqual(p1.elo, 1516) {
+		t.Fail()
+		t.Logf("Expected P1 Elo %f, got %f\n", 1516.0, p1.elo)
+	}
+	if !almostEqual(p2.elo, 1500) {
+		t.Fail()
+		t.Logf("Expected P2 Elo %f, got %f\n", 1500.0, p2.elo)
+	}
+	if !almostEqual(p3.elo, 1484) {
+		t.Fail()
+		t.Logf("Expected P3 Elo %f, got %f\n", 1484.0, p3.elo)
+	}
+
+	// a second call does nothing
+	m.Play([]int{3, 2, 1})
+	if !almostEqual(p1.elo, 1516) {
+		t.Fail()
+		t.Log("The same matched played twice should not alter elo twice.")
+	}
+}
+
+func TestMultiMatchTies(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+
+	players := []elo.Player{&player{1600}, &player{1500}, &player{1400}, &player{1300}}
+	m := c.NewMultiMatch(players...)
+	if err := m.Play([]int{2, 1, 1, 4}); err != nil {
+		t.Fatal(err)
+	}
+
+	var total float64
+	for _, p := range players {
+		total += p.GetElo()
+	}
+	if !almostEqual(total, 5800) {
+		t.Fail()
+		t.Logf("Total elo must not change, got %f\n", total)
+	}
+	if players[2].GetElo() <= 1400 || players[0].GetElo() >= 1600 {
+		t.Fail()
+		t.Logf("Unexpected ratings %f and %f\n", players[2].GetElo(), players[0].GetElo())
+	}
+
+	// ties ignored
+	c = elo.NewCalculatorBuilder().WithIgnoreDraws().Build()
+	p1 := &player{1500}
+	p2 := &player{1500}
+	m = c.NewMultiMatch(p1, p2)
+	m.SetKValue(64)
+	m.SetKValue(-1) // will be ignored
+	m.Play([]int{1, 1})
+	if !almostEqual(p1.elo, 1500) || !almostEqual(p2.elo, 1500) {
+		t.Fail()
+		t.Logf("Draw not ignored, got %f and %f\n", p1.elo, p2.elo)
+	}
+	if !almostEqual(m.GetKValue(), 64) {
+		t.Fail()
+		t.Logf("Failed to override K value.")
+	}
+
+	if err := c.NewMultiMatch(p1, p2).Play([]int{1}); err != elo.ErrPlacementMismatch {
+		t.Fail()
+		t.Logf("Expected %v, got %v\n", elo.ErrPlacementMismatch, err)
+	}
+}
+
+func TestMultiMatchStrategy(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+
+	p1 := &player{1500}
+	p2 := &player{1500}
+	m := c.NewMultiMatch(p1, p2)
+	m.SetStrategy(func(input *elo.CalculatorInput) (float64, float64) {
+		return input.PlayerOne + 1, input.PlayerTwo - 1
+	})
+	m.Play([]int{1, 2})
+
+	if !almostEqual(p1.elo, 1501) || !almostEqual(p2.elo, 1499) {
+		t.Fail()
+		t.Logf("Overriden strategy failed.")
+	}
+}
+
+func TestMultiMatchScoredStrategies(t *testing.T) {
+	tests := []struct {
+		strategy elo.StrategyFunc
+		change   float64
+	}{
+		// every pairing is a 1-0 win, so scores add nothing to the default strategy
+		{elo.StrategyScored, 16},
+		// a margin of 1 between even players multiplies K by ln(2)
+		{elo.StrategyMarginOfVictory, 16 * math.Ln2},
+	}
+	for _, test := range tests {
+		c := elo.NewCalculatorBuilder().WithStrategy(test.strategy).Build()
+		p1, p2, p3 := &player{1500}, &player{1500}, &player{1500}
+		if err := c.NewMultiMatch(p1, p2, p3).Play([]int{1, 2, 3}); err != nil {
+			t.Fatal(err)
+		}
+		if !almostEqual(p1.elo, 1500+test.change) || !almostEqual(p2.elo, 1500) || !almostEqual(p3.elo, 1500-test.change) {
+			t.Fail()
+			t.Logf("Expected P1 Elo %f, got %f, %f and %f\n", 1500+test.change, p1.elo, p2.elo, p3.elo)
+		}
+	}
+
+	// ties are rated as draws
+	c := elo.NewCalculatorBuilder().WithStrategy(elo.StrategyScored).Build()
+	p1, p2 := &player{1600}, &player{1400}
+	c.NewMultiMatch(p1, p2).Play([]int{1, 1})
+	if p1.elo >= 1600 || p2.elo <= 1400 {
+		t.Fail()
+		t.Logf("Expected a tie to favor the lower rated player, got %f and %f\n", p1.elo, p2.elo)
+	}
+}
+
+func TestMultiMatchKFactor(t *testing.T) {
+	c := elo.NewCalculatorBuilder().WithKFactor(elo.KFactorFIDE).Build()
+
+	// a new player has K 40 and an established one K 20, both shared between 2 opponents
+	p1 := &experiencedPlayer{player: player{1500}, games: 0, peak: 1500}
+	p2 := &experiencedPlayer{player: player{1500}, games: 100, peak: 1500}
+	p3 := &player{1500}
+	c.NewMultiMatch(p1, p2, p3).Play([]int{1, 2, 3})
+
+	if !almostEqual(p1.elo, 1520) || !almostEqual(p2.elo, 1500) || !almostEqual(p3.elo, 1484) {
+		t.Fail()
+		t.Logf("Expected %f, %f and %f, got %f, %f and %f\n", 1520.0, 1500.0, 1484.0, p1.elo, p2.elo, p3.elo)
+	}
+}
@@ -0,0 +1,136 @@
+package elo
+
+import "math"
+
+// Returns how good a match the players would make, between 0 and 1, for ranking
+// prospective matches. Quality is 1 minus the difference between the players' odds to
+// win from GetOdds, so an even match has a quality of 1. When the players are
+// GlickoPlayers, quality is lowered by their rating deviations as in TrueSkill, since an
+// uncertain rating makes an even match less certain, taking the rating difference that
+// gives the better player a 76% chance to win to be half the deviation.
+func (m Match) Quality() float64 {
+	return oddsQuality(m.GetOdds()) * uncertaintyQuality([]Player{m.PlayerOne, m.PlayerTwo}, m.deviation)
+}
+
+// Returns how good a match the teams would make, between 0 and 1. See Match.Quality.
+func (m *TeamMatch) Quality() float64 {
+	players := append(append([]Player{}, m.TeamOne...), m.TeamTwo...)
+	return oddsQuality(m.GetOdds()) * uncertaintyQuality(players, m.c.deviation)
+}
+
+func oddsQuality(odds *MatchOdds) float64 {
+	return 1 - math.Abs(odds.PlayerOneOdds-odds.PlayerTwoOdds)
+}
+
+// Returns sqrt(nβ² / (nβ² + Σrd²)) for the n players, where β is half the deviation and
+// players that are not GlickoPlayers have no rating deviation.
+func uncertaintyQuality(players []Player, deviation float64) float64 {
+	var variance float64
+	for _, p := range players {
+		if g, ok := p.(GlickoPlayer); ok {
+			variance += g.GetRatingDeviation() * g.GetRatingDeviation()
+		}
+	}
+	if variance == 0 {
+		return 1
+	}
+	performance := float64(len(players)) * deviation * deviation / 4
+	return math.Sqrt(performance / (performance + variance))
+}
+
+// Returns the TrueSkill match quality of the teams, between 0 and 1, which is the
+// probability of every team drawing, relative to the probability of a draw if every
+// player's skill were known and equal. Two new players have a quality of about 0.447.
+// weights is optional, and holds the fraction of the match, between 0 and 1, each player
+// would take part in.
+func (c *TrueSkillCalculator) Quality(teams [][]SkillRating, weights [][]float64) (float64, error) {
+	if err := validateTeams(teams, make([]int, len(teams)), weights); err != nil {
+		return 0, err
+	}
+	// each player's row of A has their weight in the column comparing their team with the
+	// next team, and minus their weight in the column comparing it with the previous team
+	k := len(teams) - 1
+	b := make([][]float64, k) // β²AᵀA
+	m := make([][]float64, k) // β²AᵀA + AᵀΣA
+	v := make([]float64, k)   // Aᵀμ
+	for i := range b {
+		b[i] = make([]float64, k)
+		m[i] = make([]float64, k)
+	}
+	beta2 := c.beta * c.beta
+	row := make([]float64, k)
+	for t, team := range teams {
+		for i, r := range team {
+			w := 1.0
+			if weights != nil {
+				w = weights[t][i]
+			}
+			clear(row)
+			if t < k {
+				row[t] = w
+			}
+			if t > 0 {
+				row[t-1] = -w
+			}
+			for x := range row {
+				v[x] += row[x] * r.Mu
+				for y := range row {
+					b[x][y] += beta2 * row[x] * row[y]
+					m[x][y] += (beta2 + r.Sigma*r.Sigma) * row[x] * row[y]
+				}
+			}
+		}
+	}
+	_, detB := solveLinear(b, make([]float64, k))
+	x, detM := solveLinear(m, v)
+	if detM == 0 {
+		return 0, nil
+	}
+	var exponent float64
+	for i := range v {
+		exponent += v[i] * x[i]
+	}
+	return math.Sqrt(detB/detM) * math.Exp(-exponent/2), nil
+}
+
+// Solves a·x = v by Gaussian elimination with partial pivoting, returning x and the
+// determinant of a. Neither argument is changed. x is nil if a is singular.
+func solveLinear(a [][]float64, v []float64) ([]float64, float64) {
+	n := len(a)
+	m := make([][]float64, n)
+	for i := range a {
+		m[i] = append(append(make([]float64, 0, n+1), a[i]...), v[i])
+	}
+	det := 1.0
+	for col := 0; col < n; col++ {
+		pivot := col
+		for r := col + 1; r < n; r++ {
+			if math.Abs(m[r][col]) > math.Abs(m[pivot][col]) {
+				pivot = r
+			}
+		}
+		if m[pivot][col] == 0 {
+			return nil, 0
+		}
+		if pivot != col {
+			m[pivot], m[col] = m[col], m[pivot]
+			det = -det
+		}
+		det *= m[col][col]
+		for r := col + 1; r < n; r++ {
+			f := m[r][col] / m[col][col]
+			for c := col; c <= n; c++ {
+				m[r][c] -= f * m[col][c]
+			}
+		}
+	}
+	x := make([]float64, n)
+	for r := n - 1; r >= 0; r-- {
+		x[r] = m[r][n]
+		for c := r + 1; c < n; c++ {
+			x[r] -= m[r][c] * x[c]
+		}
+		x[r] /= m[r][r]
+	}
+	return x, det
+}
@@ -0,0 +1,70 @@
+package elo_test
+
+import (
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+func TestMatchQuality(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+
+	if q := c.NewMatch(&player{1500}, &player{1500}).Quality(); !almostEqual(q, 1) {
+		t.Fail()
+		t.Logf("Expected quality %f, got %f\n", 1.0, q)
+	}
+	// 76% against 24%
+	expected := 0.480506
+	if q := c.NewMatch(&player{1600}, &player{1400}).Quality(); !almostEqual(q, expected) {
+		t.Fail()
+		t.Logf("Expected quality %f, got %f\n", expected, q)
+	}
+	// the same match between players with uncertain ratings
+	expected = 0.446862
+	p1 := &glickoPlayer{player: player{1600}, rd: 100}
+	p2 := &glickoPlayer{player: player{1400}, rd: 50}
+	if q := c.NewMatch(p1, p2).Quality(); !almostEqual(q, expected) {
+		t.Fail()
+		t.Logf("Expected quality %f, got %f\n", expected, q)
+	}
+
+	// 1000 and 1300 against 1100 and 1200
+	m := c.NewTeamMatch([]elo.Player{&player{1000}, &player{1300}}, []elo.Player{&player{1100}, &player{1200}})
+	if q := m.Quality(); !almostEqual(q, 1) {
+		t.Fail()
+		t.Logf("Expected quality %f, got %f\n", 1.0, q)
+	}
+	// team averages of 1600 and 1400
+	expected = 0.480506
+	m = c.NewTeamMatch([]elo.Player{&player{1500}, &player{1700}}, []elo.Player{&player{1400}, &player{1400}})
+	if q := m.Quality(); !almostEqual(q, expected) {
+		t.Fail()
+		t.Logf("Expected quality %f, got %f\n", expected, q)
+	}
+}
+
+func TestTrueSkillQuality(t *testing.T) {
+	c := elo.NewTrueSkillCalculatorBuilder().Build()
+	r := c.NewRating()
+
+	tests := []struct {
+		teams    [][]elo.SkillRating
+		expected float64
+	}{
+		{[][]elo.SkillRating{{r}, {r}}, 0.447214},
+		{[][]elo.SkillRating{{r}, {r}, {r}}, 0.2},
+		{[][]elo.SkillRating{{{Mu: 30, Sigma: 4}, {Mu: 20, Sigma: 5}}, {{Mu: 27, Sigma: 3}, {Mu: 24, Sigma: 6}}}, 0.666245},
+	}
+	for _, test := range tests {
+		q, err := c.Quality(test.teams, nil)
+		if err != nil || !almostEqual(q, test.expected) {
+			t.Fail()
+			t.Logf("Expected quality %f, got %f (%v)\n", test.expected, q, err)
+		}
+	}
+
+	if _, err := c.Quality([][]elo.SkillRating{{r}}, nil); err != elo.ErrTooFewTeams {
+		t.Fail()
+		t.Logf("Expected ErrTooFewTeams, got %v\n", err)
+	}
+}
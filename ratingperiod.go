@@ -0,0 +1,175 @@
+package elo
+
+// Collects the results of many matches and applies them all at once. Every match is
+// rated using each player's rating from before the period, and each player's changes
+// from all of their matches are added together, so the order matches were added in
+// does not matter. This is how leagues that publish ratings once per period, such as
+// FIDE's monthly lists, rate games.
+//
+// Apply rates the period with its strategy, and only updates elo ratings. ApplyGlicko and
+// ApplyGlicko2 instead rate each player once over all of their games in the period, as
+// the Glicko systems intend, and also update the rating deviation and volatility of
+// every GlickoPlayer, including the growth in rating deviation of an IdlePlayer.
+type RatingPeriod struct {
+	c       Calculator
+	matches []periodMatch
+}
+
+type periodMatch struct {
+	playerOne Player
+	playerTwo Player
+	result    MatchResult
+}
+
+func (c *Calculator) NewRatingPeriod() *RatingPeriod {
+	return &RatingPeriod{c: *c}
+}
+
+// Set a strategy to be used for this period only.
+func (r *RatingPeriod) SetStrategy(sf StrategyFunc) {
+	r.c.strategy = sf
+}
+
+// Adds the result of a match to the period. Args p1 and p2 should be non-nil pointers.
+func (r *RatingPeriod) Add(p1, p2 Player, result *MatchResult) {
+	r.matches = append(r.matches, periodMatch{
+		playerOne: p1,
+		playerTwo: p2,
+		result:    *result,
+	})
+}
+
+// Returns the number of matches added since the period was last applied.
+func (r *RatingPeriod) Len() int {
+	return len(r.matches)
+}
+
+// Rates every match added to the period with the period's strategy and updates each
+// player's elo. Rating deviations and volatilities are not changed, whatever the
+// strategy. Afterwards the period is empty, and may be used to collect the results of
+// the next period.
+func (r *RatingPeriod) Apply() {
+	ratings := make(map[Player]float64)
+	deltas := make(map[Player]float64)
+	order := r.players()
+	for _, p := range order {
+		ratings[p] = p.GetElo()
+	}
+
+	for _, pm := range r.matches {
+		if !r.rated(pm) {
+			continue
+		}
+		m := r.c.NewMatch(pm.playerOne, pm.playerTwo)
+		input := m.input(&pm.result)
+		n1, n2 := m.strategy(input)
+		deltas[pm.playerOne] += n1 - input.PlayerOne
+		deltas[pm.playerTwo] += n2 - input.PlayerTwo
+	}
+
+	for _, p := range order {
+		p.SetElo(ratings[p] + deltas[p])
+	}
+	r.matches = nil
+}
+
+// Rates each player once over all of their games in the period with the original Glicko
+// system, using the calculator's deviation and constant c, and updates their elo and
+// rating deviation. Afterwards the period is empty.
+func (r *RatingPeriod) ApplyGlicko() {
+	r.applyGlicko(false)
+	r.matches = nil
+}
+
+// Rates each player once over all of their games in the period with Glicko-2, using the
+// calculator's tau and deviation, and updates their elo, rating deviation and
+// volatility. Afterwards the period is empty.
+func (r *RatingPeriod) ApplyGlicko2() {
+	r.applyGlicko(true)
+	r.matches = nil
+}
+
+// Rates each player once over all of their games in the period, against their opponents'
+// ratings from the start of the period.
+func (r *RatingPeriod) applyGlicko(glicko2 bool) {
+	order := r.players()
+	ratings := make(map[Player]GlickoRating, len(order))
+	for _, p := range order {
+		ratings[p] = r.startRating(p, glicko2)
+	}
+
+	results := make(map[Player][]GlickoResult, len(order))
+	for _, pm := range r.matches {
+		if !r.rated(pm) {
+			continue
+		}
+		S1, S2 := outcomeScores(pm.result.Outcome)
+		results[pm.playerOne] = append(results[pm.playerOne],
+			GlickoResult{Opponent: shifted(ratings[pm.playerTwo], -r.c.advantage), Score: S1})
+		results[pm.playerTwo] = append(results[pm.playerTwo],
+			GlickoResult{Opponent: shifted(ratings[pm.playerOne], r.c.advantage), Score: S2})
+	}
+
+	for _, p := range order {
+		var n GlickoRating
+		if glicko2 {
+			n = glicko2Period(ratings[p], results[p], r.c.tau, r.c.deviation)
+		} else {
+			n = glickoPeriod(ratings[p], results[p], r.c.deviation)
+		}
+		p.SetElo(n.Rating)
+		if g, ok := p.(GlickoPlayer); ok {
+			g.SetRatingDeviation(n.RatingDeviation)
+			g.SetVolatility(n.Volatility)
+		}
+	}
+}
+
+// Returns the player's rating at the start of the period, with the rating deviation
+// grown for any periods they were idle.
+func (r *RatingPeriod) startRating(p Player, glicko2 bool) GlickoRating {
+	rating := GlickoRating{
+		Rating:          p.GetElo(),
+		RatingDeviation: DefaultRatingDeviation,
+		Volatility:      DefaultVolatility,
+	}
+	if g, ok := p.(GlickoPlayer); ok {
+		rating.RatingDeviation = orDefault(g.GetRatingDeviation(), DefaultRatingDeviation)
+		rating.Volatility = g.GetVolatility()
+		if glicko2 {
+			rating.Volatility = orDefault(rating.Volatility, DefaultVolatility)
+		}
+	}
+	var idle float64
+	if i, ok := p.(IdlePlayer); ok {
+		idle = i.GetIdlePeriods()
+	}
+	if glicko2 {
+		return glicko2Inactivity(rating, idle, r.c.deviation)
+	}
+	return glickoInactivity(rating, idle, r.c.glickoC)
+}
+
+// Returns every player in the period, in the order they were first added.
+func (r *RatingPeriod) players() []Player {
+	seen := make(map[Player]bool)
+	var order []Player
+	for _, pm := range r.matches {
+		for _, p := range []Player{pm.playerOne, pm.playerTwo} {
+			if !seen[p] {
+				seen[p] = true
+				order = append(order, p)
+			}
+		}
+	}
+	return order
+}
+
+// Reports whether the match changes any ratings. Matches against oneself and ignored
+// draws do not.
+func (r *RatingPeriod) rated(pm periodMatch) bool {
+	return pm.playerOne != pm.playerTwo &&
+		!(pm.result.Outcome == OutcomeDraw &&
+			r.c.ignoreDraws &&
+			pm.result.PlayerOneScore == pm.result.PlayerTwoScore)
+}
@@ -0,0 +1,146 @@
+package elo_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+func TestRatingPeriod(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+
+	for _, reversed := range []bool{false, true} {
+		a, b, d := &player{1600}, &player{1800}, &player{1500}
+		r := c.NewRatingPeriod()
+		games := []func(){
+			func() { r.Add(a, b, &elo.MatchResult{Outcome: elo.OutcomePlayerTwoWin}) },
+			func() { r.Add(a, d, &elo.MatchResult{Outcome: elo.OutcomePlayerOneWin}) },
+		}
+		if reversed {
+			games[0], games[1] = games[1], games[0]
+		}
+		for _, g := range games {
+			g()
+		}
+		if r.Len() != 2 || !almostEqual(a.elo, 1600) {
+			t.Fail()
+			t.Log("Ratings must not change before the period is applied")
+		}
+		r.Apply()
+
+		// both of a's matches are rated from 1600
+		if !almostEqual(a.elo, 1603.829822) || !almostEqual(b.elo, 1807.688098) || !almostEqual(d.elo, 1488.482080) {
+			t.Fail()
+			t.Logf("Expected %f, %f and %f, got %f, %f and %f\n", 1603.829822, 1807.688098, 1488.482080,
+				a.elo, b.elo, d.elo)
+		}
+		if r.Len() != 0 {
+			t.Fail()
+			t.Log("Expected the period to be empty after being applied")
+		}
+	}
+}
+
+func TestRatingPeriodIgnoreDraws(t *testing.T) {
+	c := elo.NewCalculatorBuilder().
+		WithIgnoreDraws().
+		Build()
+
+	a, b := &player{1600}, &player{1800}
+	r := c.NewRatingPeriod()
+	r.SetStrategy(elo.StrategyScored)
+	r.Add(a, b, &elo.MatchResult{PlayerOneScore: 2, PlayerTwoScore: 2})
+	r.Add(a, a, &elo.MatchResult{Outcome: elo.OutcomePlayerOneWin})
+	r.Apply()
+
+	if !almostEqual(a.elo, 1600) || !almostEqual(b.elo, 1800) {
+		t.Fail()
+		t.Logf("Expected no change, got %f and %f\n", a.elo, b.elo)
+	}
+}
+
+func TestRatingPeriodGlicko(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+
+	// examples from Glickman's "The Glicko system" and "Example of the Glicko-2 system",
+	// where the player is rated once over all three games
+	for _, tc := range []struct {
+		glicko2 bool
+		period  func(elo.GlickoRating, []elo.GlickoResult) elo.GlickoRating
+		rating  float64
+		rd      float64
+	}{
+		{false, c.GlickoPeriod, 1464, 151.4},
+		{true, c.Glicko2Period, 1464.06, 151.52},
+	} {
+		a := &glickoPlayer{player{1500}, 200, 0.06}
+		b := &glickoPlayer{player{1400}, 30, 0.06}
+		d := &glickoPlayer{player{1550}, 100, 0.06}
+		e := &glickoPlayer{player{1700}, 300, 0.06}
+		r := c.NewRatingPeriod()
+		r.Add(a, b, &elo.MatchResult{Outcome: elo.OutcomePlayerOneWin})
+		r.Add(a, d, &elo.MatchResult{Outcome: elo.OutcomePlayerTwoWin})
+		r.Add(e, a, &elo.MatchResult{Outcome: elo.OutcomePlayerOneWin})
+		if tc.glicko2 {
+			r.ApplyGlicko2()
+		} else {
+			r.ApplyGlicko()
+		}
+		if r.Len() != 0 {
+			t.Fail()
+			t.Log("Expected the period to be empty after being applied")
+		}
+
+		if math.Abs(a.elo-tc.rating) > 0.5 || math.Abs(a.rd-tc.rd) > 0.05 {
+			t.Fail()
+			t.Logf("Expected rating %f and rating deviation %f, got %f and %f\n", tc.rating, tc.rd, a.elo, a.rd)
+		}
+
+		// opponents are rated against the player's rating from the start of the period
+		start := elo.GlickoRating{Rating: 1500, RatingDeviation: 200, Volatility: 0.06}
+		want := tc.period(elo.GlickoRating{Rating: 1550, RatingDeviation: 100, Volatility: 0.06},
+			[]elo.GlickoResult{{Opponent: start, Score: 1}})
+		if !almostEqual(d.elo, want.Rating) || !almostEqual(d.rd, want.RatingDeviation) || !almostEqual(d.vol, want.Volatility) {
+			t.Fail()
+			t.Logf("Expected %f, %f and %f, got %f, %f and %f\n", want.Rating, want.RatingDeviation, want.Volatility,
+				d.elo, d.rd, d.vol)
+		}
+	}
+}
+
+func TestRatingPeriodGlickoInactivity(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+
+	a := &idlePlayer{glickoPlayer{player{1500}, 50, 0.06}, 4}
+	b := &glickoPlayer{player{1500}, 50, 0.06}
+	r := c.NewRatingPeriod()
+	r.Add(a, b, &elo.MatchResult{Outcome: elo.OutcomeDraw})
+	r.ApplyGlicko()
+
+	// a's rating deviation grows for the idle periods before the period is rated
+	start := c.GlickoInactivity(elo.GlickoRating{Rating: 1500, RatingDeviation: 50}, 4)
+	want := c.GlickoPeriod(start, []elo.GlickoResult{{Opponent: elo.GlickoRating{Rating: 1500, RatingDeviation: 50}, Score: 0.5}})
+	if !almostEqual(a.rd, want.RatingDeviation) || a.rd <= b.rd {
+		t.Fail()
+		t.Logf("Expected rating deviation %f, got %f\n", want.RatingDeviation, a.rd)
+	}
+}
+
+func TestRatingPeriodGlickoAdvantage(t *testing.T) {
+	// an advantage of 100 rates the same as player one being 100 higher
+	a1, a2 := &glickoPlayer{player{1500}, 200, 0.06}, &glickoPlayer{player{1500}, 100, 0.06}
+	r := elo.NewCalculatorBuilder().WithAdvantage(100).Build().NewRatingPeriod()
+	r.Add(a1, a2, &elo.MatchResult{Outcome: elo.OutcomePlayerOneWin})
+	r.ApplyGlicko2()
+
+	b1, b2 := &glickoPlayer{player{1600}, 200, 0.06}, &glickoPlayer{player{1500}, 100, 0.06}
+	r = elo.NewCalculatorBuilder().Build().NewRatingPeriod()
+	r.Add(b1, b2, &elo.MatchResult{Outcome: elo.OutcomePlayerOneWin})
+	r.ApplyGlicko2()
+
+	if !almostEqual(a1.elo+100, b1.elo) || !almostEqual(a2.elo, b2.elo) {
+		t.Fail()
+		t.Logf("Expected %f and %f, got %f and %f\n", b1.elo-100, b2.elo, a1.elo, a2.elo)
+	}
+}
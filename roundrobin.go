@@ -0,0 +1,277 @@
+package elo
+
+import (
+	"math"
+	"sort"
+)
+
+// A match in a round-robin tournament, between players identified by their index in
+// RoundRobin.Players. The home player is the match's player one.
+type RoundRobinMatch struct {
+	*Match
+
+	// Rounds are numbered from 1.
+	Round int
+	Home  int
+	Away  int
+
+	result *MatchResult
+}
+
+// Plays the match and records the result in the tournament's standings. A result with
+// a winner by score is scored and rated as that player's win, whatever its Outcome. Can
+// only be called once. Any subsequent calls will result in no changes.
+func (m *RoundRobinMatch) Play(result *MatchResult) {
+	if m.result != nil {
+		return
+	}
+	m.result = scoredResult(result)
+	m.Match.Play(m.result)
+}
+
+// Returns the result of the match, or nil if it has not been played.
+func (m *RoundRobinMatch) Result() *MatchResult {
+	return m.result
+}
+
+// A player's standing in a round-robin tournament.
+type RoundRobinStanding struct {
+	Player int
+	Played int
+
+	// 1 for each win and 0.5 for each draw.
+	Score float64
+
+	// The sum of the scores of every opponent the player beat, and half the scores of
+	// every opponent they drew with.
+	SonnebornBerger float64
+
+	// The score the player got in matches against the players they are tied with on
+	// both Score and SonnebornBerger.
+	HeadToHead float64
+
+	// The rating at which the player's expected score against their opponents, rated as
+	// they were when the tournament began, equals the score they got.
+	Performance float64
+}
+
+// A tournament where every player plays every other player once, or twice in a double
+// round robin, scheduled with the circle method used for Berger tables. With an odd
+// number of players, one player sits out each round. Home and away matches are balanced,
+// so every player is at home in half of their matches, or as close to half as possible,
+// and in a double round robin the second half repeats the first with home and away
+// swapped.
+type RoundRobin struct {
+	Players []Player
+
+	ratings []float64
+	c       Calculator
+	rounds  [][]*RoundRobinMatch
+	byes    []int
+}
+
+// Args players should be non-nil pointers.
+func (c *Calculator) NewRoundRobin(players ...Player) *RoundRobin {
+	return c.newRoundRobin(players, false)
+}
+
+// Args players should be non-nil pointers.
+func (c *Calculator) NewDoubleRoundRobin(players ...Player) *RoundRobin {
+	return c.newRoundRobin(players, true)
+}
+
+func (c *Calculator) newRoundRobin(players []Player, double bool) *RoundRobin {
+	r := &RoundRobin{
+		Players: players,
+		ratings: make([]float64, len(players)),
+		c:       *c,
+	}
+	for i, p := range players {
+		r.ratings[i] = p.GetElo()
+	}
+
+	// with an odd number of players, whoever meets the extra player n sits out
+	n := len(players)
+	if n%2 == 1 {
+		n++
+	}
+	for round := 0; round < n-1; round++ {
+		var pairs [][2]int
+		for board := 0; board < n/2; board++ {
+			home, away := (round+board)%(n-1), (round-board+n-1)%(n-1)
+			if board == 0 {
+				// the fixed player is at home every other round
+				home, away = round, n-1
+				if round%2 == 1 {
+					home, away = away, home
+				}
+			}
+			pairs = append(pairs, [2]int{home, away})
+		}
+		r.addRound(pairs, false)
+	}
+	if double {
+		for round := 0; round < n-1; round++ {
+			var pairs [][2]int
+			for _, m := range r.rounds[round] {
+				pairs = append(pairs, [2]int{m.Home, m.Away})
+			}
+			if round < len(r.byes) && r.byes[round] >= 0 {
+				pairs = append(pairs, [2]int{r.byes[round], len(players)})
+			}
+			r.addRound(pairs, true)
+		}
+	}
+	return r
+}
+
+// Adds a round of the given pairs, optionally swapping home and away. A pair with a
+// player outside the tournament is a bye.
+func (r *RoundRobin) addRound(pairs [][2]int, swap bool) {
+	number := len(r.rounds) + 1
+	bye := -1
+	var round []*RoundRobinMatch
+	for _, pair := range pairs {
+		home, away := pair[0], pair[1]
+		if swap {
+			home, away = away, home
+		}
+		if home >= len(r.Players) {
+			bye = away
+			continue
+		}
+		if away >= len(r.Players) {
+			bye = home
+			continue
+		}
+		round = append(round, &RoundRobinMatch{
+			Match: r.c.NewMatch(r.Players[home], r.Players[away]),
+			Round: number,
+			Home:  home,
+			Away:  away,
+		})
+	}
+	r.rounds = append(r.rounds, round)
+	r.byes = append(r.byes, bye)
+}
+
+// Returns the number of rounds in the tournament.
+func (r *RoundRobin) Rounds() int {
+	return len(r.rounds)
+}
+
+// Returns the matches of a round, numbered from 1.
+func (r *RoundRobin) Round(n int) []*RoundRobinMatch {
+	if n < 1 || n > len(r.rounds) {
+		return nil
+	}
+	return append([]*RoundRobinMatch(nil), r.rounds[n-1]...)
+}
+
+// Returns the player sitting out a round, numbered from 1, or -1 if every player plays.
+func (r *RoundRobin) Bye(n int) int {
+	if n < 1 || n > len(r.byes) {
+		return -1
+	}
+	return r.byes[n-1]
+}
+
+// Returns the schedule of every match, for use with a Simulator.
+func (r *RoundRobin) Schedule() Schedule {
+	var s Schedule
+	for _, round := range r.rounds {
+		for _, m := range round {
+			s = append(s, ScheduledMatch{PlayerOne: m.Home, PlayerTwo: m.Away})
+		}
+	}
+	return s
+}
+
+// Returns every player's standing from the matches played so far, best first. Players
+// are ordered by score, then by Sonneborn-Berger score, then by their score in matches
+// between the players they are still tied with, and then by performance rating.
+func (r *RoundRobin) Standings() []RoundRobinStanding {
+	n := len(r.Players)
+	standings := make([]RoundRobinStanding, n)
+	// scores[i][j] is the total score of player i in matches against player j
+	scores := make([][]float64, n)
+	opponents := make([][]float64, n)
+	for i := range standings {
+		standings[i].Player = i
+		scores[i] = make([]float64, n)
+	}
+	for _, round := range r.rounds {
+		for _, m := range round {
+			if m.result == nil {
+				continue
+			}
+			S1 := resultScore(m.result)
+			scores[m.Home][m.Away] += S1
+			scores[m.Away][m.Home] += 1 - S1
+			standings[m.Home].Score += S1
+			standings[m.Away].Score += 1 - S1
+			standings[m.Home].Played++
+			standings[m.Away].Played++
+			opponents[m.Home] = append(opponents[m.Home], r.ratings[m.Away])
+			opponents[m.Away] = append(opponents[m.Away], r.ratings[m.Home])
+		}
+	}
+
+	for i := range standings {
+		for j := range standings {
+			// a player's score against an opponent already counts wins as 1 and draws as 0.5
+			standings[i].SonnebornBerger += scores[i][j] * standings[j].Score
+		}
+		standings[i].Performance = r.performance(opponents[i], standings[i].Score)
+	}
+
+	sort.SliceStable(standings, func(a, b int) bool {
+		if standings[a].Score != standings[b].Score {
+			return standings[a].Score > standings[b].Score
+		}
+		return standings[a].SonnebornBerger > standings[b].SonnebornBerger
+	})
+	// head to head between players tied on both scores
+	for start := 0; start < n; {
+		end := start + 1
+		for end < n && standings[end].Score == standings[start].Score &&
+			standings[end].SonnebornBerger == standings[start].SonnebornBerger {
+			end++
+		}
+		tied := standings[start:end]
+		for a := range tied {
+			tied[a].HeadToHead = 0
+			for b := range tied {
+				tied[a].HeadToHead += scores[tied[a].Player][tied[b].Player]
+			}
+		}
+		sort.SliceStable(tied, func(a, b int) bool {
+			if tied[a].HeadToHead != tied[b].HeadToHead {
+				return tied[a].HeadToHead > tied[b].HeadToHead
+			}
+			return tied[a].Performance > tied[b].Performance
+		})
+		start = end
+	}
+	return standings
+}
+
+// Returns the performance rating for the score against opponents with the given
+// ratings, limited to twice the deviation beyond the weakest and strongest opponent.
+func (r *RoundRobin) performance(opponents []float64, score float64) float64 {
+	if len(opponents) == 0 {
+		return 0
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, o := range opponents {
+		lo, hi = math.Min(lo, o), math.Max(hi, o)
+	}
+	return bisect(func(rating float64) float64 {
+		surplus := score
+		for _, o := range opponents {
+			odds := davidsonOdds(rating, o, r.c.deviation, r.c.drawParameter)
+			surplus -= odds.PlayerOneOdds + odds.DrawOdds/2
+		}
+		return surplus
+	}, lo-2*r.c.deviation, hi+2*r.c.deviation)
+}
@@ -0,0 +1,192 @@
+package elo_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+// Checks that every pair of players meets the given number of times, and returns how
+// many home matches each player has.
+func checkRoundRobin(t *testing.T, r *elo.RoundRobin, meetings int) []int {
+	n := len(r.Players)
+	met := make(map[[2]int]int)
+	home := make([]int, n)
+	for round := 1; round <= r.Rounds(); round++ {
+		seen := make(map[int]bool)
+		for _, m := range r.Round(round) {
+			if seen[m.Home] || seen[m.Away] {
+				t.Fail()
+				t.Logf("Round %d: a player plays twice\n", round)
+			}
+			seen[m.Home], seen[m.Away] = true, true
+			met[[2]int{min(m.Home, m.Away), max(m.Home, m.Away)}]++
+			home[m.Home]++
+		}
+		if bye := r.Bye(round); bye >= 0 {
+			seen[bye] = true
+		}
+		if len(seen) != n {
+			t.Fail()
+			t.Logf("Round %d: expected every player to play or sit out, got %d\n", round, len(seen))
+		}
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if met[[2]int{i, j}] != meetings {
+				t.Fail()
+				t.Logf("Expected players %d and %d to meet %d times, got %d\n", i, j, meetings, met[[2]int{i, j}])
+			}
+		}
+	}
+	return home
+}
+
+func TestRoundRobin(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+
+	r := c.NewRoundRobin(swissPlayers(6)...)
+	if r.Rounds() != 5 {
+		t.Fail()
+		t.Logf("Expected 5 rounds, got %d\n", r.Rounds())
+	}
+	for p, h := range checkRoundRobin(t, r, 1) {
+		if h < 2 || h > 3 {
+			t.Fail()
+			t.Logf("Expected player %d to have 2 or 3 home matches, got %d\n", p, h)
+		}
+	}
+	if len(r.Schedule()) != 15 {
+		t.Fail()
+		t.Logf("Expected 15 scheduled matches, got %d\n", len(r.Schedule()))
+	}
+}
+
+func TestRoundRobinOdd(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+
+	r := c.NewRoundRobin(swissPlayers(5)...)
+	if r.Rounds() != 5 {
+		t.Fail()
+		t.Logf("Expected 5 rounds, got %d\n", r.Rounds())
+	}
+	for p, h := range checkRoundRobin(t, r, 1) {
+		if h != 2 {
+			t.Fail()
+			t.Logf("Expected player %d to have 2 home matches, got %d\n", p, h)
+		}
+	}
+	byes := make(map[int]bool)
+	for round := 1; round <= r.Rounds(); round++ {
+		byes[r.Bye(round)] = true
+	}
+	if len(byes) != 5 {
+		t.Fail()
+		t.Logf("Expected every player to sit out once, got %v\n", byes)
+	}
+}
+
+func TestDoubleRoundRobin(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+
+	r := c.NewDoubleRoundRobin(swissPlayers(5)...)
+	if r.Rounds() != 10 {
+		t.Fail()
+		t.Logf("Expected 10 rounds, got %d\n", r.Rounds())
+	}
+	for p, h := range checkRoundRobin(t, r, 2) {
+		if h != 4 {
+			t.Fail()
+			t.Logf("Expected player %d to have 4 home matches, got %d\n", p, h)
+		}
+	}
+	// each pair meets once at each player's home
+	for round := 1; round <= 5; round++ {
+		for i, m := range r.Round(round) {
+			second := r.Round(round + 5)[i]
+			if second.Home != m.Away || second.Away != m.Home {
+				t.Fail()
+				t.Logf("Expected round %d to swap round %d\n", round+5, round)
+			}
+		}
+	}
+}
+
+func TestRoundRobinStandings(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	players := []elo.Player{&player{1500}, &player{1500}, &player{1500}, &player{1500}}
+	r := c.NewRoundRobin(players...)
+
+	// score of the first player of each pair
+	results := map[[2]int]float64{
+		{0, 1}: 0, {0, 2}: 1, {0, 3}: 0.5, {1, 2}: 0.5, {1, 3}: 0, {2, 3}: 0.5,
+	}
+	for round := 1; round <= r.Rounds(); round++ {
+		for _, m := range r.Round(round) {
+			score, ok := results[[2]int{m.Home, m.Away}]
+			if !ok {
+				score = 1 - results[[2]int{m.Away, m.Home}]
+			}
+			outcome := elo.OutcomeDraw
+			if score == 1 {
+				outcome = elo.OutcomePlayerOneWin
+			} else if score == 0 {
+				outcome = elo.OutcomePlayerTwoWin
+			}
+			m.Play(&elo.MatchResult{Outcome: outcome})
+			m.Play(&elo.MatchResult{Outcome: outcome}) // will be ignored
+		}
+	}
+
+	s := r.Standings()
+	// players 0 and 1 are tied on score and Sonneborn-Berger, but 1 beat 0
+	order := []int{3, 1, 0, 2}
+	for i, st := range s {
+		if st.Player != order[i] {
+			t.Fail()
+			t.Logf("Expected player %d in place %d, got %d\n", order[i], i+1, st.Player)
+		}
+		if st.Played != 3 {
+			t.Fail()
+			t.Logf("Expected 3 matches played, got %d\n", st.Played)
+		}
+	}
+	if !almostEqual(s[0].Score, 2) || !almostEqual(s[0].SonnebornBerger, 2.75) {
+		t.Fail()
+		t.Logf("Expected score %f and Sonneborn-Berger %f, got %f and %f\n", 2.0, 2.75, s[0].Score, s[0].SonnebornBerger)
+	}
+	if !almostEqual(s[1].SonnebornBerger, 2) || !almostEqual(s[1].HeadToHead, 1) || !almostEqual(s[2].HeadToHead, 0) {
+		t.Fail()
+		t.Logf("Unexpected tie break %+v and %+v\n", s[1], s[2])
+	}
+	// scoring two thirds against players rated 1500
+	expected := 1500 + 400*math.Log10(2)
+	if !almostEqual(s[0].Performance, expected) {
+		t.Fail()
+		t.Logf("Expected performance %f, got %f\n", expected, s[0].Performance)
+	}
+	if almostEqual(players[3].GetElo(), 1500) {
+		t.Fail()
+		t.Log("Expected results to be rated")
+	}
+}
+
+func TestRoundRobinScoredResult(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	players := []elo.Player{&player{1500}, &player{1500}}
+	r := c.NewRoundRobin(players...)
+
+	// a win by score counts in both the standings and the ratings
+	m := r.Round(1)[0]
+	m.Play(&elo.MatchResult{PlayerOneScore: 3, PlayerTwoScore: 1})
+	s := r.Standings()
+	if s[0].Player != m.Home || !almostEqual(s[0].Score, 1) || !almostEqual(players[m.Home].GetElo(), 1516) {
+		t.Fail()
+		t.Logf("Expected the home player to win and gain %f, got %+v and %f\n", 16.0, s[0], players[m.Home].GetElo())
+	}
+	if m.Result().Outcome != elo.OutcomePlayerOneWin {
+		t.Fail()
+		t.Logf("Expected the result to be recorded as a win, got %v\n", m.Result().Outcome)
+	}
+}
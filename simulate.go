@@ -0,0 +1,319 @@
+package elo
+
+import (
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// Number of tournaments simulated with each random source. Every chunk has its own
+// source seeded from the simulator's seed, so results do not depend on how chunks are
+// spread across goroutines.
+const simulationChunk = 256
+
+// A tournament format that can be played out by a Simulator. Simulate may be called from
+// several goroutines at once, so it must not change the tournament.
+type SimulatedTournament interface {
+	// Plays out the tournament once between n players, deciding every match with play
+	// and any other random choice, such as breaking ties, with rng. Returns each
+	// player's finishing place, where 0 is first and -1 means the player did not take
+	// part, and the number of rounds each player went through, or nil if the format
+	// has no rounds.
+	Simulate(n int, play func(p1, p2 int) MatchOutcome, rng *rand.Rand) (places []int, rounds []int)
+}
+
+// Simulates tournaments many times to estimate how likely each player is to finish in
+// each place. Matches are decided using the odds from GetOdds, including the odds of a
+// draw when the calculator has a draw parameter. Ratings are read once when the
+// simulation starts, and are not changed by simulated matches.
+type Simulator struct {
+	Players    []Player
+	c          Calculator
+	seed       int64
+	iterations int
+	workers    int
+}
+
+// Args players should be non-nil pointers. By default, tournaments are simulated 10000
+// times with a seed of 1, using every available core.
+func (c *Calculator) NewSimulator(players ...Player) *Simulator {
+	return &Simulator{
+		Players:    players,
+		c:          *c,
+		seed:       1,
+		iterations: 10000,
+		workers:    runtime.GOMAXPROCS(0),
+	}
+}
+
+// Simulations with the same seed, players and tournament give the same results,
+// whatever the number of workers.
+func (s *Simulator) SetSeed(seed int64) {
+	s.seed = seed
+}
+
+// Iterations must be greater than 0. If a non-positive value is provided, the number
+// of iterations will be unchanged.
+func (s *Simulator) SetIterations(n int) {
+	if n <= 0 {
+		return
+	}
+	s.iterations = n
+}
+
+// Workers must be greater than 0. If a non-positive value is provided, the number of
+// workers will be unchanged.
+func (s *Simulator) SetWorkers(n int) {
+	if n <= 0 {
+		return
+	}
+	s.workers = n
+}
+
+// The results of simulating a tournament.
+type SimulationResult struct {
+	Iterations int
+
+	// Places[i][p] is the probability that Players[i] finishes in place p, where 0 is first.
+	Places [][]float64
+
+	// Rounds[i][r] is the probability that Players[i] reaches round r, where round 0 is
+	// the first round. Nil if the tournament has no rounds.
+	Rounds [][]float64
+}
+
+// Returns the probability that Players[i] wins the tournament.
+func (r *SimulationResult) WinProbability(i int) float64 {
+	return r.Places[i][0]
+}
+
+// Simulates the tournament using the simulator's players, indexed by their position
+// in Players.
+func (s *Simulator) Run(t SimulatedTournament) *SimulationResult {
+	n := len(s.Players)
+	ratings := make([]float64, n)
+	for i, p := range s.Players {
+		ratings[i] = p.GetElo()
+	}
+	play := func(rng *rand.Rand) func(p1, p2 int) MatchOutcome {
+		return func(p1, p2 int) MatchOutcome {
+			odds := davidsonOdds(ratings[p1]+s.c.advantage, ratings[p2], s.c.deviation, s.c.drawParameter)
+			u := rng.Float64()
+			if u < odds.PlayerOneOdds {
+				return OutcomePlayerOneWin
+			} else if u < odds.PlayerOneOdds+odds.PlayerTwoOdds {
+				return OutcomePlayerTwoWin
+			}
+			return OutcomeDraw
+		}
+	}
+
+	chunks := (s.iterations + simulationChunk - 1) / simulationChunk
+	total := newSimulationCounts(n)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	next := make(chan int)
+
+	for w := 0; w < s.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			counts := newSimulationCounts(n)
+			for chunk := range next {
+				rng := rand.New(rand.NewSource(chunkSeed(s.seed, chunk)))
+				size := min(simulationChunk, s.iterations-chunk*simulationChunk)
+				for i := 0; i < size; i++ {
+					counts.add(t.Simulate(n, play(rng), rng))
+				}
+			}
+			mu.Lock()
+			total.merge(counts)
+			mu.Unlock()
+		}()
+	}
+	for chunk := 0; chunk < chunks; chunk++ {
+		next <- chunk
+	}
+	close(next)
+	wg.Wait()
+
+	return total.result(s.iterations)
+}
+
+// How many times each player finished in each place and went through each number of rounds.
+type simulationCounts struct {
+	places    [][]int
+	rounds    [][]int
+	hasRounds bool
+}
+
+func newSimulationCounts(n int) *simulationCounts {
+	c := &simulationCounts{
+		places: make([][]int, n),
+		rounds: make([][]int, n),
+	}
+	for p := range c.places {
+		c.places[p] = make([]int, n)
+	}
+	return c
+}
+
+func (c *simulationCounts) add(places, rounds []int) {
+	for p, place := range places {
+		if place >= 0 {
+			c.places[p][place]++
+		}
+	}
+	if rounds != nil {
+		c.hasRounds = true
+	}
+	for p, r := range rounds {
+		if r < 0 {
+			continue
+		}
+		for len(c.rounds[p]) <= r {
+			c.rounds[p] = append(c.rounds[p], 0)
+		}
+		c.rounds[p][r]++
+	}
+}
+
+func (c *simulationCounts) merge(other *simulationCounts) {
+	for p := range other.places {
+		for place, count := range other.places[p] {
+			c.places[p][place] += count
+		}
+		for len(c.rounds[p]) < len(other.rounds[p]) {
+			c.rounds[p] = append(c.rounds[p], 0)
+		}
+		for r, count := range other.rounds[p] {
+			c.rounds[p][r] += count
+		}
+	}
+	c.hasRounds = c.hasRounds || other.hasRounds
+}
+
+func (c *simulationCounts) result(iterations int) *SimulationResult {
+	result := &SimulationResult{
+		Iterations: iterations,
+		Places:     make([][]float64, len(c.places)),
+	}
+	maxRounds := 0
+	for p := range c.places {
+		result.Places[p] = make([]float64, len(c.places[p]))
+		for place, count := range c.places[p] {
+			result.Places[p][place] = float64(count) / float64(iterations)
+		}
+		maxRounds = max(maxRounds, len(c.rounds[p]))
+	}
+	if !c.hasRounds {
+		return result
+	}
+	result.Rounds = make([][]float64, len(c.rounds))
+	for p := range c.rounds {
+		result.Rounds[p] = make([]float64, maxRounds)
+		// a player that went through r rounds reached every round up to r
+		var reached int
+		for r := len(c.rounds[p]) - 1; r >= 0; r-- {
+			reached += c.rounds[p][r]
+			result.Rounds[p][r] = float64(reached) / float64(iterations)
+		}
+	}
+	return result
+}
+
+// Mixes the seed and chunk index into a well spread seed for the chunk's random source.
+func chunkSeed(seed int64, chunk int) int64 {
+	z := uint64(seed) + uint64(chunk+1)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return int64(z ^ (z >> 31))
+}
+
+// A match in a Schedule, between players identified by their index.
+type ScheduledMatch struct {
+	PlayerOne int
+	PlayerTwo int
+}
+
+// A fixed list of matches, such as a league season. Players score 1 for a win and 0.5
+// for a draw, and are placed by their total score, with ties broken at random.
+type Schedule []ScheduledMatch
+
+func (s Schedule) Simulate(n int, play func(p1, p2 int) MatchOutcome, rng *rand.Rand) ([]int, []int) {
+	scores := make([]float64, n)
+	for _, m := range s {
+		S1, S2 := outcomeScores(play(m.PlayerOne, m.PlayerTwo))
+		scores[m.PlayerOne] += S1
+		scores[m.PlayerTwo] += S2
+	}
+	order := rng.Perm(n)
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+	places := make([]int, n)
+	for place, p := range order {
+		places[p] = place
+	}
+	return places, nil
+}
+
+// A single elimination bracket. Each element is the player in that slot of the first
+// round, or -1 for a bye, and the winners of neighboring slots meet in the next round.
+// The number of slots must be a power of two. Drawn matches are replayed.
+//
+// Players eliminated in the same round share the best place of that round, so both
+// losing semi-finalists finish in place 2.
+type Knockout []int
+
+func (k Knockout) Simulate(n int, play func(p1, p2 int) MatchOutcome, rng *rand.Rand) ([]int, []int) {
+	places := make([]int, n)
+	rounds := make([]int, n)
+	for p := range places {
+		places[p] = -1
+		rounds[p] = -1
+	}
+	for _, p := range k {
+		if p >= 0 {
+			rounds[p] = 0
+		}
+	}
+	alive := append([]int(nil), k...)
+	for round := 0; len(alive) > 1; round++ {
+		next := make([]int, len(alive)/2)
+		var losers []int
+		advancing := 0
+		for i := range next {
+			p1, p2 := alive[2*i], alive[2*i+1]
+			winner, loser := p1, p2
+			if p1 < 0 {
+				winner, loser = p2, -1
+			} else if p2 >= 0 {
+				outcome := play(p1, p2)
+				for outcome == OutcomeDraw {
+					outcome = play(p1, p2)
+				}
+				if outcome == OutcomePlayerTwoWin {
+					winner, loser = p2, p1
+				}
+			}
+			if loser >= 0 {
+				losers = append(losers, loser)
+			}
+			if winner >= 0 {
+				rounds[winner] = round + 1
+				advancing++
+			}
+			next[i] = winner
+		}
+		for _, p := range losers {
+			places[p] = advancing
+		}
+		alive = next
+	}
+	if len(alive) == 1 && alive[0] >= 0 {
+		places[alive[0]] = 0
+	}
+	return places, rounds
+}
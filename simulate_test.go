@@ -0,0 +1,121 @@
+package elo_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+// Returns the odds of a beating b under the default calculator.
+func winOdds(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+func TestSimulateSchedule(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	players := []elo.Player{&player{1600}, &player{1500}, &player{1400}}
+	schedule := elo.Schedule{{0, 1}, {1, 2}, {2, 0}}
+
+	s := c.NewSimulator(players...)
+	s.SetIterations(20000)
+	r := s.Run(schedule)
+
+	// player 0 finishes first outright by winning both matches, or on a random tie break
+	// after one win if every player won once
+	p01, p12, p02 := winOdds(1600, 1500), winOdds(1500, 1400), winOdds(1600, 1400)
+	expected := p01*p02 + (p01*p12*(1-p02)+(1-p01)*(1-p12)*p02)/3
+	if math.Abs(r.WinProbability(0)-expected) > 0.01 {
+		t.Fail()
+		t.Logf("Expected win probability %f, got %f\n", expected, r.WinProbability(0))
+	}
+	for p := range players {
+		var sum float64
+		for _, prob := range r.Places[p] {
+			sum += prob
+		}
+		if !almostEqual(sum, 1) {
+			t.Fail()
+			t.Logf("Expected place probabilities to sum to 1, got %f\n", sum)
+		}
+	}
+	if r.Rounds != nil {
+		t.Fail()
+		t.Log("Expected no rounds for a schedule")
+	}
+}
+
+func TestSimulateDeterministic(t *testing.T) {
+	c := elo.NewCalculatorBuilder().
+		WithDrawParameter(0.5).
+		Build()
+	players := []elo.Player{&player{1600}, &player{1500}, &player{1400}, &player{1450}}
+	schedule := elo.Schedule{{0, 1}, {2, 3}, {0, 2}, {1, 3}, {0, 3}, {1, 2}}
+
+	s := c.NewSimulator(players...)
+	s.SetIterations(5000)
+	s.SetSeed(42)
+	s.SetWorkers(1)
+	r1 := s.Run(schedule)
+	s.SetWorkers(8)
+	r2 := s.Run(schedule)
+	if !reflect.DeepEqual(r1, r2) {
+		t.Fail()
+		t.Log("Expected the same results with any number of workers")
+	}
+
+	s.SetSeed(43)
+	if r3 := s.Run(schedule); reflect.DeepEqual(r1, r3) {
+		t.Fail()
+		t.Log("Expected different results with a different seed")
+	}
+}
+
+func TestSimulateKnockout(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	ratings := []float64{1700, 1600, 1500, 1400}
+	players := make([]elo.Player, len(ratings))
+	for i, r := range ratings {
+		players[i] = &player{r}
+	}
+
+	s := c.NewSimulator(players...)
+	s.SetIterations(20000)
+	r := s.Run(elo.Knockout{0, 3, 1, 2})
+
+	p := func(a, b int) float64 { return winOdds(ratings[a], ratings[b]) }
+	expected := p(0, 3) * (p(1, 2)*p(0, 1) + p(2, 1)*p(0, 2))
+	if math.Abs(r.WinProbability(0)-expected) > 0.01 {
+		t.Fail()
+		t.Logf("Expected win probability %f, got %f\n", expected, r.WinProbability(0))
+	}
+	if !almostEqual(r.Rounds[0][0], 1) || math.Abs(r.Rounds[0][1]-p(0, 3)) > 0.01 ||
+		!almostEqual(r.Rounds[0][2], r.WinProbability(0)) {
+		t.Fail()
+		t.Logf("Unexpected rounds %v\n", r.Rounds[0])
+	}
+	// losing semi-finalists share third place
+	if !almostEqual(r.Places[3][2]+r.Places[3][1]+r.Places[3][0], 1) || r.Places[3][3] != 0 {
+		t.Fail()
+		t.Logf("Unexpected places %v\n", r.Places[3])
+	}
+}
+
+func TestSimulateKnockoutByes(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	players := []elo.Player{&player{1500}, &player{1500}, &player{1500}, &player{1500}}
+
+	s := c.NewSimulator(players...)
+	s.SetIterations(1000)
+	r := s.Run(elo.Knockout{0, -1, 1, 2})
+
+	if !almostEqual(r.Rounds[0][1], 1) {
+		t.Fail()
+		t.Logf("Expected a bye to always advance, got %f\n", r.Rounds[0][1])
+	}
+	if r.Rounds[3][0] != 0 || r.Places[3][0] != 0 {
+		t.Fail()
+		t.Log("Expected a player outside the bracket to never take part")
+	}
+}
@@ -0,0 +1,307 @@
+// Package sqlitestore provides an elo.PlayerStore backed by SQLite, using a pure Go
+// driver so that no C toolchain is needed.
+//
+// Playing a match through a Store updates both players and records the match in a
+// single transaction, so ratings are never left half-applied.
+package sqlitestore
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gabehf/go-elo"
+	_ "modernc.org/sqlite"
+)
+
+// Each migration is run once, in order, and the number of migrations applied is kept
+// in the database's user_version. New migrations must only ever be appended.
+var migrations = []string{
+	`CREATE TABLE players (
+		id               TEXT PRIMARY KEY,
+		elo              REAL NOT NULL,
+		rating_deviation REAL NOT NULL DEFAULT 0,
+		volatility       REAL NOT NULL DEFAULT 0,
+		games_played     INTEGER NOT NULL DEFAULT 0,
+		peak_elo         REAL NOT NULL DEFAULT 0
+	);
+	CREATE TABLE matches (
+		id                INTEGER PRIMARY KEY AUTOINCREMENT,
+		played_at         INTEGER NOT NULL,
+		player_one        TEXT NOT NULL REFERENCES players(id),
+		player_two        TEXT NOT NULL REFERENCES players(id),
+		outcome           INTEGER NOT NULL,
+		player_one_score  INTEGER NOT NULL,
+		player_two_score  INTEGER NOT NULL,
+		player_one_before REAL NOT NULL,
+		player_one_after  REAL NOT NULL,
+		player_two_before REAL NOT NULL,
+		player_two_after  REAL NOT NULL
+	);`,
+	`CREATE INDEX matches_player_one ON matches (player_one, id);
+	CREATE INDEX matches_player_two ON matches (player_two, id);`,
+}
+
+// A match as recorded by Store.Play.
+type MatchRecord struct {
+	ID        int64
+	PlayedAt  time.Time
+	PlayerOne string
+	PlayerTwo string
+	Result    elo.MatchResult
+
+	PlayerOneBefore float64
+	PlayerOneAfter  float64
+	PlayerTwoBefore float64
+	PlayerTwoAfter  float64
+}
+
+// An elo.PlayerStore kept in a SQLite database. Safe for concurrent use, including
+// by several processes sharing the same database file.
+type Store struct {
+	db *sql.DB
+}
+
+// Opens the database at the given path, creating it if it does not exist, and brings
+// its schema up to date.
+func Open(path string) (*Store, error) {
+	dsn := "file:" + path +
+		"?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)" +
+		// take the write lock when a transaction begins, so that two updates reading
+		// the same players cannot both go on to write them
+		"&_txlock=immediate"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{db: db}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Closes the database. The store must not be used afterwards.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Applies every migration the database has not seen yet, each in its own transaction.
+func (s *Store) migrate() error {
+	for {
+		done, err := s.migrateOnce()
+		if err != nil || done {
+			return err
+		}
+	}
+}
+
+// Applies the next migration, returning true if the schema was already up to date.
+func (s *Store) migrateOnce() (bool, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var version int
+	if err := tx.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return false, err
+	}
+	if version >= len(migrations) {
+		return true, nil
+	}
+	if _, err := tx.Exec(migrations[version]); err != nil {
+		return false, err
+	}
+	// PRAGMA does not accept bound parameters
+	if _, err := tx.Exec("PRAGMA user_version = " + strconv.Itoa(version+1)); err != nil {
+		return false, err
+	}
+	return false, tx.Commit()
+}
+
+func (s *Store) Get(id string) (elo.PlayerRecord, error) {
+	return get(s.db, id)
+}
+
+func (s *Store) Put(p elo.PlayerRecord) error {
+	return put(s.db, p)
+}
+
+func (s *Store) List() ([]elo.PlayerRecord, error) {
+	return list(s.db)
+}
+
+func (s *Store) Update(id1, id2 string, fn func(p1, p2 *elo.PlayerRecord) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := (txStore{tx}).Update(id1, id2, fn); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Plays a match between two players in the store, as elo.Calculator.PlayByID does, and
+// records it. The new ratings and the match record are written in one transaction.
+// A match that is not rated, such as an ignored draw, is not recorded.
+func (s *Store) Play(c *elo.Calculator, id1, id2 string, result *elo.MatchResult) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	ts := txStore{tx}
+	var before1, before2 elo.PlayerRecord
+	if before1, err = ts.Get(id1); err != nil {
+		return err
+	}
+	if before2, err = ts.Get(id2); err != nil {
+		return err
+	}
+	rated, err := c.PlayByID(ts, id1, id2, result)
+	if err != nil || !rated {
+		return err
+	}
+	after1, err := ts.Get(id1)
+	if err != nil {
+		return err
+	}
+	after2, err := ts.Get(id2)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO matches (played_at, player_one, player_two, outcome,
+		player_one_score, player_two_score, player_one_before, player_one_after,
+		player_two_before, player_two_after) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		time.Now().UnixNano(), id1, id2, int(result.Outcome),
+		result.PlayerOneScore, result.PlayerTwoScore, before1.Elo, after1.Elo,
+		before2.Elo, after2.Elo)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Returns every recorded match the player took part in, oldest first.
+func (s *Store) History(id string) ([]MatchRecord, error) {
+	rows, err := s.db.Query(`SELECT id, played_at, player_one, player_two, outcome,
+		player_one_score, player_two_score, player_one_before, player_one_after,
+		player_two_before, player_two_after
+		FROM matches WHERE player_one = ? OR player_two = ? ORDER BY id`, id, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []MatchRecord
+	for rows.Next() {
+		var m MatchRecord
+		var playedAt int64
+		var outcome int
+		err := rows.Scan(&m.ID, &playedAt, &m.PlayerOne, &m.PlayerTwo, &outcome,
+			&m.Result.PlayerOneScore, &m.Result.PlayerTwoScore, &m.PlayerOneBefore,
+			&m.PlayerOneAfter, &m.PlayerTwoBefore, &m.PlayerTwoAfter)
+		if err != nil {
+			return nil, err
+		}
+		m.PlayedAt = time.Unix(0, playedAt)
+		m.Result.Outcome = elo.MatchOutcome(outcome)
+		history = append(history, m)
+	}
+	return history, rows.Err()
+}
+
+// Either a database or a transaction.
+type querier interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func get(q querier, id string) (elo.PlayerRecord, error) {
+	var p elo.PlayerRecord
+	err := q.QueryRow(`SELECT id, elo, rating_deviation, volatility, games_played, peak_elo
+		FROM players WHERE id = ?`, id).
+		Scan(&p.ID, &p.Elo, &p.RatingDeviation, &p.Volatility, &p.GamesPlayed, &p.PeakElo)
+	if errors.Is(err, sql.ErrNoRows) {
+		return elo.PlayerRecord{}, elo.ErrPlayerNotFound
+	}
+	return p, err
+}
+
+func list(q querier) ([]elo.PlayerRecord, error) {
+	rows, err := q.Query(`SELECT id, elo, rating_deviation, volatility, games_played, peak_elo
+		FROM players ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var players []elo.PlayerRecord
+	for rows.Next() {
+		var p elo.PlayerRecord
+		if err := rows.Scan(&p.ID, &p.Elo, &p.RatingDeviation, &p.Volatility, &p.GamesPlayed, &p.PeakElo); err != nil {
+			return nil, err
+		}
+		players = append(players, p)
+	}
+	return players, rows.Err()
+}
+
+func put(q querier, p elo.PlayerRecord) error {
+	_, err := q.Exec(`INSERT INTO players (id, elo, rating_deviation, volatility, games_played, peak_elo)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET elo = excluded.elo,
+			rating_deviation = excluded.rating_deviation, volatility = excluded.volatility,
+			games_played = excluded.games_played, peak_elo = excluded.peak_elo`,
+		p.ID, p.Elo, p.RatingDeviation, p.Volatility, p.GamesPlayed, p.PeakElo)
+	return err
+}
+
+// An elo.PlayerStore whose every change is part of a single transaction, which the
+// caller commits or rolls back.
+type txStore struct {
+	tx *sql.Tx
+}
+
+func (s txStore) Get(id string) (elo.PlayerRecord, error) {
+	return get(s.tx, id)
+}
+
+func (s txStore) Put(p elo.PlayerRecord) error {
+	return put(s.tx, p)
+}
+
+func (s txStore) List() ([]elo.PlayerRecord, error) {
+	return list(s.tx)
+}
+
+func (s txStore) Update(id1, id2 string, fn func(p1, p2 *elo.PlayerRecord) error) error {
+	if id1 == id2 {
+		return elo.ErrSamePlayer
+	}
+	p1, err := get(s.tx, id1)
+	if err != nil {
+		return err
+	}
+	p2, err := get(s.tx, id2)
+	if err != nil {
+		return err
+	}
+	if err := fn(&p1, &p2); err != nil {
+		return err
+	}
+	p1.ID, p2.ID = id1, id2
+	if err := put(s.tx, p1); err != nil {
+		return err
+	}
+	return put(s.tx, p2)
+}
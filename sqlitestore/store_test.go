@@ -0,0 +1,148 @@
+package sqlitestore_test
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/gabehf/go-elo"
+	"github.com/gabehf/go-elo/sqlitestore"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-6
+}
+
+func openStore(t *testing.T) (*sqlitestore.Store, string) {
+	path := filepath.Join(t.TempDir(), "elo.db")
+	s, err := sqlitestore.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, path
+}
+
+func TestStore(t *testing.T) {
+	s, path := openStore(t)
+
+	if _, err := s.Get("alice"); !errors.Is(err, elo.ErrPlayerNotFound) {
+		t.Fail()
+		t.Logf("Expected ErrPlayerNotFound, got %v\n", err)
+	}
+	s.Put(elo.PlayerRecord{ID: "alice", Elo: 1600})
+	s.Put(elo.PlayerRecord{ID: "bob", Elo: 1800})
+
+	c := elo.NewCalculatorBuilder().Build()
+	err := s.Play(c, "alice", "bob", &elo.MatchResult{
+		Outcome: elo.OutcomePlayerTwoWin,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := s.Get("alice")
+	if !almostEqual(a.Elo, 1592.311902) || a.GamesPlayed != 1 {
+		t.Fail()
+		t.Logf("Expected %f after 1 game, got %f after %d\n", 1592.311902, a.Elo, a.GamesPlayed)
+	}
+
+	// a failed update changes neither player
+	failed := errors.New("failed")
+	err = s.Update("alice", "bob", func(p1, p2 *elo.PlayerRecord) error {
+		p1.Elo = 0
+		p2.Elo = 0
+		return failed
+	})
+	if a, _ := s.Get("alice"); err != failed || !almostEqual(a.Elo, 1592.311902) {
+		t.Fail()
+		t.Logf("Expected a failed update to be rolled back, got %v and %f\n", err, a.Elo)
+	}
+	if err := s.Play(c, "alice", "carol", &elo.MatchResult{}); !errors.Is(err, elo.ErrPlayerNotFound) {
+		t.Fail()
+		t.Logf("Expected ErrPlayerNotFound, got %v\n", err)
+	}
+	s.Close()
+
+	// migrations are only applied once
+	s, err = sqlitestore.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	players, _ := s.List()
+	if len(players) != 2 || players[0].ID != "alice" || players[1].ID != "bob" {
+		t.Fail()
+		t.Logf("Expected players sorted by ID, got %+v\n", players)
+	}
+}
+
+func TestStoreHistory(t *testing.T) {
+	s, _ := openStore(t)
+	defer s.Close()
+	s.Put(elo.PlayerRecord{ID: "alice", Elo: 1600})
+	s.Put(elo.PlayerRecord{ID: "bob", Elo: 1800})
+	s.Put(elo.PlayerRecord{ID: "carol", Elo: 1500})
+
+	c := elo.NewCalculatorBuilder().WithIgnoreDraws().Build()
+	s.Play(c, "alice", "bob", &elo.MatchResult{Outcome: elo.OutcomePlayerTwoWin})
+	s.Play(c, "bob", "carol", &elo.MatchResult{Outcome: elo.OutcomePlayerOneWin})
+	s.Play(c, "carol", "alice", &elo.MatchResult{Outcome: elo.OutcomeDraw}) // not rated
+
+	h, err := s.History("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h) != 2 {
+		t.Fatalf("Expected 2 matches, got %d\n", len(h))
+	}
+	if h[0].PlayerOne != "alice" || h[0].Result.Outcome != elo.OutcomePlayerTwoWin ||
+		!almostEqual(h[0].PlayerTwoBefore, 1800) || !almostEqual(h[0].PlayerTwoAfter, 1807.688098) {
+		t.Fail()
+		t.Logf("Unexpected first match %+v\n", h[0])
+	}
+	if !almostEqual(h[1].PlayerOneBefore, h[0].PlayerTwoAfter) {
+		t.Fail()
+		t.Logf("Expected the second match to start from %f, got %f\n", h[0].PlayerTwoAfter, h[1].PlayerOneBefore)
+	}
+	if h, _ := s.History("carol"); len(h) != 1 {
+		t.Fail()
+		t.Logf("Expected an ignored draw not to be recorded, got %d matches\n", len(h))
+	}
+}
+
+func TestStoreConcurrentPlay(t *testing.T) {
+	s, _ := openStore(t)
+	defer s.Close()
+	s.Put(elo.PlayerRecord{ID: "alice", Elo: 1500})
+	s.Put(elo.PlayerRecord{ID: "bob", Elo: 1500})
+
+	// every match must be applied on top of the last, so the sum of ratings is kept
+	c := elo.NewCalculatorBuilder().Build()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			outcome := elo.OutcomePlayerOneWin
+			if i%2 == 0 {
+				outcome = elo.OutcomePlayerTwoWin
+			}
+			if err := s.Play(c, "alice", "bob", &elo.MatchResult{Outcome: outcome}); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	a, _ := s.Get("alice")
+	b, _ := s.Get("bob")
+	if a.GamesPlayed != 20 || !almostEqual(a.Elo+b.Elo, 3000) {
+		t.Fail()
+		t.Logf("Expected 20 games and a total of %f, got %d and %f\n", 3000.0, a.GamesPlayed, a.Elo+b.Elo)
+	}
+	if h, _ := s.History("alice"); len(h) != 20 {
+		t.Fail()
+		t.Logf("Expected 20 matches, got %d\n", len(h))
+	}
+}
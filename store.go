@@ -0,0 +1,160 @@
+package elo
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync"
+)
+
+var (
+	ErrPlayerNotFound = errors.New("elo: player not found")
+	ErrSamePlayer     = errors.New("elo: a player cannot play against themselves")
+)
+
+// A player as kept by a PlayerStore. A pointer to a PlayerRecord implements Player,
+// GlickoPlayer and ExperiencedPlayer, so it can be used directly in a match.
+type PlayerRecord struct {
+	ID              string  `json:"id"`
+	Elo             float64 `json:"elo"`
+	RatingDeviation float64 `json:"rating_deviation,omitempty"`
+	Volatility      float64 `json:"volatility,omitempty"`
+	GamesPlayed     int     `json:"games_played"`
+	PeakElo         float64 `json:"peak_elo"`
+}
+
+func (p *PlayerRecord) GetElo() float64 {
+	return p.Elo
+}
+func (p *PlayerRecord) SetElo(elo float64) {
+	p.Elo = elo
+}
+func (p *PlayerRecord) GetRatingDeviation() float64 {
+	return p.RatingDeviation
+}
+func (p *PlayerRecord) SetRatingDeviation(rd float64) {
+	p.RatingDeviation = rd
+}
+func (p *PlayerRecord) GetVolatility() float64 {
+	return p.Volatility
+}
+func (p *PlayerRecord) SetVolatility(v float64) {
+	p.Volatility = v
+}
+func (p *PlayerRecord) GetGamesPlayed() int {
+	return p.GamesPlayed
+}
+func (p *PlayerRecord) GetPeakElo() float64 {
+	return p.PeakElo
+}
+
+// Keeps player records between matches. Implementations must be safe for concurrent use.
+type PlayerStore interface {
+	// Returns the player with the given ID, or ErrPlayerNotFound.
+	Get(id string) (PlayerRecord, error)
+
+	// Adds or replaces a player.
+	Put(p PlayerRecord) error
+
+	// Returns every player, sorted by ID.
+	List() ([]PlayerRecord, error)
+
+	// Loads two different players and passes them to fn. If fn returns nil, both players
+	// are stored as fn left them, under their original IDs, in a single atomic write.
+	// Otherwise, neither player is changed and the error is returned.
+	Update(id1, id2 string, fn func(p1, p2 *PlayerRecord) error) error
+}
+
+// Plays a match between two players in the store and saves their new ratings. Each
+// player's games played and peak elo are updated if the match was rated. Returns
+// whether the match was rated, which it is not if it is an ignored draw.
+func (c *Calculator) PlayByID(store PlayerStore, id1, id2 string, result *MatchResult) (bool, error) {
+	var rated bool
+	err := store.Update(id1, id2, func(p1, p2 *PlayerRecord) error {
+		if c.NewMatch(p1, p2).play(result) == nil {
+			return nil
+		}
+		for _, p := range []*PlayerRecord{p1, p2} {
+			p.GamesPlayed++
+			p.PeakElo = math.Max(p.PeakElo, p.Elo)
+		}
+		rated = true
+		return nil
+	})
+	return rated && err == nil, err
+}
+
+// A PlayerStore that keeps players in memory.
+type MemoryStore struct {
+	mu      sync.Mutex
+	players map[string]PlayerRecord
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		players: make(map[string]PlayerRecord),
+	}
+}
+
+func (s *MemoryStore) Get(id string) (PlayerRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.players[id]
+	if !ok {
+		return PlayerRecord{}, ErrPlayerNotFound
+	}
+	return p, nil
+}
+
+func (s *MemoryStore) Put(p PlayerRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.players[p.ID] = p
+	return nil
+}
+
+func (s *MemoryStore) List() ([]PlayerRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedRecords(s.players), nil
+}
+
+func (s *MemoryStore) Update(id1, id2 string, fn func(p1, p2 *PlayerRecord) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p1, p2, err := loadPair(s.players, id1, id2)
+	if err != nil {
+		return err
+	}
+	if err := fn(&p1, &p2); err != nil {
+		return err
+	}
+	p1.ID, p2.ID = id1, id2
+	s.players[id1] = p1
+	s.players[id2] = p2
+	return nil
+}
+
+// Returns copies of two different players.
+func loadPair(players map[string]PlayerRecord, id1, id2 string) (PlayerRecord, PlayerRecord, error) {
+	if id1 == id2 {
+		return PlayerRecord{}, PlayerRecord{}, ErrSamePlayer
+	}
+	p1, ok1 := players[id1]
+	p2, ok2 := players[id2]
+	if !ok1 || !ok2 {
+		return PlayerRecord{}, PlayerRecord{}, ErrPlayerNotFound
+	}
+	return p1, p2, nil
+}
+
+func sortedRecords(players map[string]PlayerRecord) []PlayerRecord {
+	records := make([]PlayerRecord, 0, len(players))
+	for _, p := range players {
+		records = append(records, p)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].ID < records[j].ID
+	})
+	return records
+}
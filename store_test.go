@@ -0,0 +1,77 @@
+package elo_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+func TestMemoryStore(t *testing.T) {
+	testPlayerStore(t, elo.NewMemoryStore())
+}
+
+// Runs the same checks against any PlayerStore implementation.
+func testPlayerStore(t *testing.T, s elo.PlayerStore) {
+	if _, err := s.Get("alice"); !errors.Is(err, elo.ErrPlayerNotFound) {
+		t.Fail()
+		t.Logf("Expected ErrPlayerNotFound, got %v\n", err)
+	}
+	s.Put(elo.PlayerRecord{ID: "bob", Elo: 1800})
+	s.Put(elo.PlayerRecord{ID: "alice", Elo: 1600})
+
+	c := elo.NewCalculatorBuilder().Build()
+	rated, err := c.PlayByID(s, "alice", "bob", &elo.MatchResult{
+		Outcome: elo.OutcomePlayerTwoWin,
+	})
+	if err != nil || !rated {
+		t.Fatal(err)
+	}
+	a, _ := s.Get("alice")
+	b, _ := s.Get("bob")
+	if !almostEqual(a.Elo, 1592.311902) || !almostEqual(b.Elo, 1807.688098) {
+		t.Fail()
+		t.Logf("Expected %f and %f, got %f and %f\n", 1592.311902, 1807.688098, a.Elo, b.Elo)
+	}
+	if a.GamesPlayed != 1 || b.GamesPlayed != 1 || !almostEqual(a.PeakElo, 1592.311902) || !almostEqual(b.PeakElo, 1807.688098) {
+		t.Fail()
+		t.Logf("Expected games and peaks to be updated, got %+v and %+v\n", a, b)
+	}
+
+	// an ignored draw is not rated
+	ignore := elo.NewCalculatorBuilder().WithIgnoreDraws().Build()
+	if rated, err := ignore.PlayByID(s, "alice", "bob", &elo.MatchResult{}); err != nil || rated {
+		t.Fail()
+		t.Logf("Expected an ignored draw not to be rated, got %v and %v\n", rated, err)
+	}
+	if a, _ := s.Get("alice"); a.GamesPlayed != 1 {
+		t.Fail()
+		t.Logf("Expected games played %d, got %d\n", 1, a.GamesPlayed)
+	}
+
+	// a failed update changes neither player
+	failed := errors.New("failed")
+	err = s.Update("alice", "bob", func(p1, p2 *elo.PlayerRecord) error {
+		p1.Elo = 0
+		return failed
+	})
+	if a, _ := s.Get("alice"); err != failed || !almostEqual(a.Elo, 1592.311902) {
+		t.Fail()
+		t.Logf("Expected a failed update to be discarded, got %v and %f\n", err, a.Elo)
+	}
+
+	if _, err := c.PlayByID(s, "alice", "carol", &elo.MatchResult{}); !errors.Is(err, elo.ErrPlayerNotFound) {
+		t.Fail()
+		t.Logf("Expected ErrPlayerNotFound, got %v\n", err)
+	}
+	if _, err := c.PlayByID(s, "alice", "alice", &elo.MatchResult{}); !errors.Is(err, elo.ErrSamePlayer) {
+		t.Fail()
+		t.Logf("Expected ErrSamePlayer, got %v\n", err)
+	}
+
+	players, _ := s.List()
+	if len(players) != 2 || players[0].ID != "alice" || players[1].ID != "bob" {
+		t.Fail()
+		t.Logf("Expected players sorted by ID, got %+v\n", players)
+	}
+}
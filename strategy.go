@@ -9,11 +9,7 @@ type StrategyFunc func(input *CalculatorInput) (r1 float64, r2 float64)
 // Calculates elo based on a Win/Loss system.
 func StrategyDefault(input *CalculatorInput) (float64, float64) {
 
-	R1 := math.Pow(10, input.PlayerOne/input.Deviation)
-	R2 := math.Pow(10, input.PlayerTwo/input.Deviation)
-
-	E1 := R1 / (R1 + R2)
-	E2 := R2 / (R1 + R2)
+	E1, E2 := expectedScores(input)
 
 	var S1, S2 float64
 	switch input.Outcome {
@@ -28,8 +24,9 @@ func StrategyDefault(input *CalculatorInput) (float64, float64) {
 		S2 = 0.5
 	}
 
-	NewP1 := input.PlayerOne + input.K*(S1-E1)
-	NewP2 := input.PlayerTwo + input.K*(S2-E2)
+	K1, K2 := input.kValues()
+	NewP1 := input.PlayerOne + K1*(S1-E1)
+	NewP2 := input.PlayerTwo + K2*(S2-E2)
 	return NewP1, NewP2
 }
 
@@ -55,11 +52,7 @@ func determineWeightedSValues(winnerScore, loserScore, winnerE, loserE, weight f
 // A more dominant score means greater elo gained.
 func StrategyScored(input *CalculatorInput) (float64, float64) {
 
-	R1 := math.Pow(10, input.PlayerOne/input.Deviation)
-	R2 := math.Pow(10, input.PlayerTwo/input.Deviation)
-
-	E1 := R1 / (R1 + R2)
-	E2 := R2 / (R1 + R2)
+	E1, E2 := expectedScores(input)
 
 	var S1, S2 float64
 	if input.PlayerOneScore == 0 {
@@ -89,7 +82,41 @@ func StrategyScored(input *CalculatorInput) (float64, float64) {
 		S2 = 0.5
 	}
 
-	NewP1 := input.PlayerOne + input.K*(S1-E1)
-	NewP2 := input.PlayerTwo + input.K*(S2-E2)
+	K1, K2 := input.kValues()
+	NewP1 := input.PlayerOne + K1*(S1-E1)
+	NewP2 := input.PlayerTwo + K2*(S2-E2)
+	return NewP1, NewP2
+}
+
+// Calculates elo using a margin of victory multiplier, in the style of FiveThirtyEight's
+// NFL model. The change in elo grows with the logarithm of the point margin, and is
+// reduced when the favorite wins to correct for autocorrelation, where favorites would
+// otherwise win by larger margins and inflate their ratings.
+func StrategyMarginOfVictory(input *CalculatorInput) (float64, float64) {
+
+	E1, E2 := expectedScores(input)
+
+	ac := input.Autocorrelation
+	if ac <= 0 {
+		ac = DefaultAutocorrelation
+	}
+
+	margin := math.Abs(float64(input.PlayerOneScore - input.PlayerTwoScore))
+	// ties count as a margin of one, without any autocorrelation correction
+	M := math.Log(math.Max(margin, 1)+1) * ac
+	var S1, S2 float64
+	if input.PlayerOneScore > input.PlayerTwoScore {
+		S1, S2 = 1, 0
+		M /= (input.PlayerOne+input.Advantage-input.PlayerTwo)*0.001 + ac
+	} else if input.PlayerOneScore < input.PlayerTwoScore {
+		S1, S2 = 0, 1
+		M /= (input.PlayerTwo-input.PlayerOne-input.Advantage)*0.001 + ac
+	} else {
+		S1, S2 = 0.5, 0.5
+	}
+
+	K1, K2 := input.kValues()
+	NewP1 := input.PlayerOne + K1*M*(S1-E1)
+	NewP2 := input.PlayerTwo + K2*M*(S2-E2)
 	return NewP1, NewP2
 }
@@ -394,3 +394,68 @@ func TestIgnoreDraw(t *testing.T) {
 		t.Logf("Expected P2 Elo %f, got %f\n", 1600.0, p2.elo)
 	}
 }
+
+func TestMarginOfVictoryStrategy(t *testing.T) {
+	c := elo.NewCalculatorBuilder().
+		WithStrategy(elo.StrategyMarginOfVictory).
+		WithKValue(20).
+		Build()
+
+	// FiveThirtyEight: evenly matched teams, winner by 7, moves ln(8) times
+	// the usual 10 points
+	n1, n2 := c.Calculate(1500, 1500, &elo.MatchResult{
+		PlayerOneScore: 24,
+		PlayerTwoScore: 17,
+	})
+	if !almostEqual(n1, 1520.794415) || !almostEqual(n2, 1479.205585) {
+		t.Fail()
+		t.Logf("Expected %f and %f, got %f and %f\n", 1520.794415, 1479.205585, n1, n2)
+	}
+
+	// a favorite winning by 3 gains less than an underdog winning by 3
+	f1, _ := c.Calculate(1600, 1500, &elo.MatchResult{
+		PlayerOneScore: 20,
+		PlayerTwoScore: 17,
+	})
+	_, u2 := c.Calculate(1600, 1500, &elo.MatchResult{
+		PlayerOneScore: 17,
+		PlayerTwoScore: 20,
+	})
+	if !almostEqual(f1, 1609.545625) {
+		t.Fail()
+		t.Logf("Expected favorite Elo %f, got %f\n", 1609.545625, f1)
+	}
+	if !almostEqual(u2, 1518.591435) {
+		t.Fail()
+		t.Logf("Expected underdog Elo %f, got %f\n", 1518.591435, u2)
+	}
+
+	// ties use a margin of one
+	n1, n2 = c.Calculate(1600, 1500, &elo.MatchResult{
+		PlayerOneScore: 20,
+		PlayerTwoScore: 20,
+	})
+	if n1 >= 1600 || !almostEqual(1600-n1, n2-1500) {
+		t.Fail()
+		t.Logf("Favorite must lose elo after a tie, got %f and %f\n", n1, n2)
+	}
+
+	// a lower autocorrelation constant reduces the favorite's gain further
+	c = elo.NewCalculatorBuilder().
+		WithStrategy(elo.StrategyMarginOfVictory).
+		WithKValue(20).
+		WithAutocorrelation(1).
+		WithAutocorrelation(-1). // will be ignored
+		Build()
+
+	p1 := &player{1600}
+	p2 := &player{1500}
+	c.NewMatch(p1, p2).Play(&elo.MatchResult{
+		PlayerOneScore: 20,
+		PlayerTwoScore: 17,
+	})
+	if p1.elo >= f1 {
+		t.Fail()
+		t.Logf("Expected less than %f, got %f\n", f1, p1.elo)
+	}
+}
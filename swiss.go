@@ -0,0 +1,341 @@
+package elo
+
+import (
+	"errors"
+	"sort"
+)
+
+var (
+	ErrRoundInProgress = errors.New("elo: the current round has unreported results")
+	ErrNoPairing       = errors.New("elo: no pairing satisfies the rules")
+	ErrBoardNotFound   = errors.New("elo: no such board in the current round")
+	ErrAlreadyReported = errors.New("elo: the result of this board has already been reported")
+)
+
+// Maximum number of pairings tried when searching for a round's pairing, so that a
+// round that cannot be paired fails quickly instead of trying every possibility.
+const swissSearchLimit = 1000000
+
+// A board in a round of a Swiss tournament, between players identified by their
+// index in Swiss.Players.
+type SwissPairing struct {
+	// Boards are numbered from 1, in order of the players' standing.
+	Board int
+
+	// Plays as player one.
+	White int
+
+	// Plays as player two, or -1 if White has a bye.
+	Black int
+
+	// Nil until the result is reported. Always nil for a bye.
+	Result *MatchResult
+}
+
+// A player's standing in a tournament.
+type SwissStanding struct {
+	Player int
+	Score  float64
+}
+
+// A Swiss-system tournament, paired following the main rules of the FIDE Dutch system.
+// Each round, players are ranked by score and then by their rating when the tournament
+// began, and are paired within their score group, with the top half of each group
+// playing the bottom half in order. Players who cannot be paired in their own group
+// float down to the next group. Two players never meet twice, no player's whites and
+// blacks differ by more than two, and no player has the same color three rounds in a
+// row unless there is no other way to pair the round. With an odd number of players,
+// the lowest ranked player who has not had a bye gets one, scoring a win.
+//
+// Results are played through the calculator, so ratings change as they are reported.
+// A win scores 1 and a draw 0.5, whether or not the calculator ignores draws.
+type Swiss struct {
+	Players []Player
+
+	c         Calculator
+	ratings   []float64
+	scores    []float64
+	colors    [][]int
+	opponents []map[int]bool
+	byes      []bool
+	rounds    [][]SwissPairing
+}
+
+// Args players should be non-nil pointers.
+func (c *Calculator) NewSwiss(players ...Player) *Swiss {
+	s := &Swiss{
+		Players:   players,
+		c:         *c,
+		ratings:   make([]float64, len(players)),
+		scores:    make([]float64, len(players)),
+		colors:    make([][]int, len(players)),
+		opponents: make([]map[int]bool, len(players)),
+		byes:      make([]bool, len(players)),
+	}
+	for i, p := range players {
+		s.ratings[i] = p.GetElo()
+		s.opponents[i] = make(map[int]bool)
+	}
+	return s
+}
+
+// Returns the pairings of the next round. Every result of the previous round must have
+// been reported first.
+func (s *Swiss) Pair() ([]SwissPairing, error) {
+	if !s.roundFinished() {
+		return nil, ErrRoundInProgress
+	}
+	order := s.ranking()
+
+	pairs, bye, ok := s.search(order, true)
+	if !ok {
+		// drop the color rules rather than fail to pair the round
+		pairs, bye, ok = s.search(order, false)
+	}
+	if !ok {
+		return nil, ErrNoPairing
+	}
+
+	round := make([]SwissPairing, 0, len(pairs)+1)
+	for i, pair := range pairs {
+		white, black := s.allocateColors(pair[0], pair[1], i+1)
+		s.colors[white] = append(s.colors[white], 1)
+		s.colors[black] = append(s.colors[black], -1)
+		s.opponents[white][black] = true
+		s.opponents[black][white] = true
+		round = append(round, SwissPairing{Board: i + 1, White: white, Black: black})
+	}
+	if bye >= 0 {
+		s.byes[bye] = true
+		s.scores[bye]++
+		round = append(round, SwissPairing{Board: len(round) + 1, White: bye, Black: -1})
+	}
+	s.rounds = append(s.rounds, round)
+	return s.Round(len(s.rounds)), nil
+}
+
+// Reports the result of a board in the current round and plays the match, updating
+// both players' ratings. A result with a winner by score is scored and rated as that
+// player's win, whatever its Outcome.
+func (s *Swiss) Report(board int, result *MatchResult) error {
+	if len(s.rounds) == 0 {
+		return ErrBoardNotFound
+	}
+	round := s.rounds[len(s.rounds)-1]
+	if board < 1 || board > len(round) || round[board-1].Black < 0 {
+		return ErrBoardNotFound
+	}
+	p := &round[board-1]
+	if p.Result != nil {
+		return ErrAlreadyReported
+	}
+	// the result is scored and rated the same way, even when only the scores are given
+	p.Result = scoredResult(result)
+
+	S1 := resultScore(p.Result)
+	s.scores[p.White] += S1
+	s.scores[p.Black] += 1 - S1
+	s.c.NewMatch(s.Players[p.White], s.Players[p.Black]).Play(p.Result)
+	return nil
+}
+
+// Returns the pairings and results of a round, numbered from 1.
+func (s *Swiss) Round(n int) []SwissPairing {
+	if n < 1 || n > len(s.rounds) {
+		return nil
+	}
+	round := make([]SwissPairing, len(s.rounds[n-1]))
+	copy(round, s.rounds[n-1])
+	return round
+}
+
+// Returns the number of rounds paired so far.
+func (s *Swiss) Rounds() int {
+	return len(s.rounds)
+}
+
+// Returns every player's score, best first. Players with the same score are ordered by
+// their rating when the tournament began.
+func (s *Swiss) Standings() []SwissStanding {
+	standings := make([]SwissStanding, len(s.Players))
+	for i, p := range s.ranking() {
+		standings[i] = SwissStanding{Player: p, Score: s.scores[p]}
+	}
+	return standings
+}
+
+func (s *Swiss) roundFinished() bool {
+	if len(s.rounds) == 0 {
+		return true
+	}
+	for _, p := range s.rounds[len(s.rounds)-1] {
+		if p.Black >= 0 && p.Result == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns every player ordered by score, then by rating when the tournament began.
+func (s *Swiss) ranking() []int {
+	order := make([]int, len(s.Players))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if s.scores[a] != s.scores[b] {
+			return s.scores[a] > s.scores[b]
+		}
+		return s.ratings[a] > s.ratings[b]
+	})
+	return order
+}
+
+// Searches for a pairing of the ranked players, returning the pairs with the higher
+// ranked player first, in order of rank, and the player given a bye or -1.
+func (s *Swiss) search(order []int, strictColors bool) ([][2]int, int, bool) {
+	steps := 0
+	var pairs [][2]int
+
+	var pairRest func(remaining []int) bool
+	pairRest = func(remaining []int) bool {
+		if len(remaining) == 0 {
+			return true
+		}
+		if steps++; steps > swissSearchLimit {
+			return false
+		}
+		p := remaining[0]
+		for _, q := range s.candidates(remaining) {
+			if s.opponents[p][q] || (strictColors && !s.colorsAllowed(p, q)) {
+				continue
+			}
+			pairs = append(pairs, [2]int{p, q})
+			if pairRest(without(remaining[1:], q)) {
+				return true
+			}
+			pairs = pairs[:len(pairs)-1]
+		}
+		return false
+	}
+
+	if len(order)%2 == 0 {
+		return pairs, -1, pairRest(order)
+	}
+	// the bye goes to the lowest ranked player who can take it, preferring players
+	// who have not had one yet
+	for _, repeat := range []bool{false, true} {
+		for i := len(order) - 1; i >= 0; i-- {
+			if s.byes[order[i]] != repeat {
+				continue
+			}
+			if pairRest(without(order, order[i])) {
+				return pairs, order[i], true
+			}
+		}
+	}
+	return nil, -1, false
+}
+
+// Returns the opponents for the first of the remaining players in order of preference:
+// the bottom half of their score group starting from its top, then the rest of the top
+// half from the bottom, then the players of lower score groups in order.
+func (s *Swiss) candidates(remaining []int) []int {
+	score := s.scores[remaining[0]]
+	group := 1
+	for group < len(remaining) && s.scores[remaining[group]] == score {
+		group++
+	}
+	half := group / 2
+	if half == 0 {
+		half = 1
+	}
+
+	candidates := make([]int, 0, len(remaining)-1)
+	candidates = append(candidates, remaining[half:group]...)
+	for i := half - 1; i >= 1; i-- {
+		candidates = append(candidates, remaining[i])
+	}
+	return append(candidates, remaining[group:]...)
+}
+
+// Reports whether the players can meet with one of them as white without either
+// breaking the color rules.
+func (s *Swiss) colorsAllowed(p, q int) bool {
+	return (s.canPlay(p, 1) && s.canPlay(q, -1)) || (s.canPlay(q, 1) && s.canPlay(p, -1))
+}
+
+// Reports whether the player can play the color, where 1 is white and -1 is black,
+// keeping their color difference within two and avoiding the same color three times
+// in a row.
+func (s *Swiss) canPlay(p, color int) bool {
+	history := s.colors[p]
+	diff := colorDifference(history) + color
+	if diff > 2 || diff < -2 {
+		return false
+	}
+	n := len(history)
+	return n < 2 || history[n-1] != color || history[n-2] != color
+}
+
+// Decides who plays white between the higher ranked player p and q. If only one way
+// keeps both players within the color rules, it is used. Otherwise, the player who has
+// had fewer whites gets white, then the player who had black most recently, and failing
+// that, p gets white on odd boards and black on even boards.
+func (s *Swiss) allocateColors(p, q, board int) (white, black int) {
+	pWhite := s.canPlay(p, 1) && s.canPlay(q, -1)
+	qWhite := s.canPlay(q, 1) && s.canPlay(p, -1)
+	if pWhite != qWhite {
+		if pWhite {
+			return p, q
+		}
+		return q, p
+	}
+	if s.canPlay(p, 1) != s.canPlay(q, 1) {
+		if s.canPlay(p, 1) {
+			return p, q
+		}
+		return q, p
+	}
+	dp, dq := colorDifference(s.colors[p]), colorDifference(s.colors[q])
+	if dp != dq {
+		if dp < dq {
+			return p, q
+		}
+		return q, p
+	}
+	// the player who had black in the most recent round where their colors differed
+	hp, hq := s.colors[p], s.colors[q]
+	for i, j := len(hp)-1, len(hq)-1; i >= 0 && j >= 0; i, j = i-1, j-1 {
+		if hp[i] != hq[j] {
+			if hp[i] < 0 {
+				return p, q
+			}
+			return q, p
+		}
+	}
+	if board%2 == 1 {
+		return p, q
+	}
+	return q, p
+}
+
+func colorDifference(history []int) int {
+	var diff int
+	for _, c := range history {
+		diff += c
+	}
+	return diff
+}
+
+// Returns a copy of the players without p.
+func without(players []int, p int) []int {
+	rest := make([]int, 0, len(players))
+	for _, q := range players {
+		if q != p {
+			rest = append(rest, q)
+		}
+	}
+	return rest
+}
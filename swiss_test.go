@@ -0,0 +1,201 @@
+package elo_test
+
+import (
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+func swissPlayers(n int) []elo.Player {
+	players := make([]elo.Player, n)
+	for i := range players {
+		players[i] = &player{2000 - 100*float64(i)}
+	}
+	return players
+}
+
+// Reports every board of the round as a win for the player who was higher rated when
+// the tournament began.
+func reportFavorites(t *testing.T, s *elo.Swiss, round []elo.SwissPairing) {
+	for _, p := range round {
+		if p.Black < 0 {
+			continue
+		}
+		outcome := elo.OutcomePlayerOneWin
+		if p.Black < p.White {
+			outcome = elo.OutcomePlayerTwoWin
+		}
+		if err := s.Report(p.Board, &elo.MatchResult{Outcome: outcome}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSwissFirstRound(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	s := c.NewSwiss(swissPlayers(8)...)
+
+	round, err := s.Pair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the top half plays the bottom half, alternating colors by board
+	expected := [][2]int{{0, 4}, {5, 1}, {2, 6}, {7, 3}}
+	for i, p := range round {
+		if p.Board != i+1 || p.White != expected[i][0] || p.Black != expected[i][1] {
+			t.Fail()
+			t.Logf("Expected board %d to be %v, got %d vs %d\n", i+1, expected[i], p.White, p.Black)
+		}
+	}
+
+	if _, err := s.Pair(); err != elo.ErrRoundInProgress {
+		t.Fail()
+		t.Logf("Expected ErrRoundInProgress, got %v\n", err)
+	}
+	if err := s.Report(1, &elo.MatchResult{Outcome: elo.OutcomePlayerOneWin}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Report(1, &elo.MatchResult{Outcome: elo.OutcomePlayerOneWin}); err != elo.ErrAlreadyReported {
+		t.Fail()
+		t.Logf("Expected ErrAlreadyReported, got %v\n", err)
+	}
+	if err := s.Report(5, &elo.MatchResult{}); err != elo.ErrBoardNotFound {
+		t.Fail()
+		t.Logf("Expected ErrBoardNotFound, got %v\n", err)
+	}
+	if !almostEqual(s.Players[0].GetElo(), 2000+32*(1-winOdds(2000, 1600))) {
+		t.Fail()
+		t.Logf("Expected the result to be rated, got %f\n", s.Players[0].GetElo())
+	}
+
+	// a win by score is rated as a win
+	if err := s.Report(2, &elo.MatchResult{PlayerOneScore: 3, PlayerTwoScore: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if !almostEqual(s.Players[5].GetElo(), 1500+32*(1-winOdds(1500, 1900))) ||
+		s.Round(1)[1].Result.Outcome != elo.OutcomePlayerOneWin {
+		t.Fail()
+		t.Logf("Expected the win by score to be rated, got %f\n", s.Players[5].GetElo())
+	}
+}
+
+func TestSwissTournament(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	s := c.NewSwiss(swissPlayers(8)...)
+
+	met := make(map[[2]int]bool)
+	colors := make([]int, 8)
+	for r := 0; r < 5; r++ {
+		round, err := s.Pair()
+		if err != nil {
+			t.Fatalf("Round %d: %v\n", r+1, err)
+		}
+		for _, p := range round {
+			key := [2]int{min(p.White, p.Black), max(p.White, p.Black)}
+			if met[key] {
+				t.Fail()
+				t.Logf("Round %d: players %d and %d met twice\n", r+1, p.White, p.Black)
+			}
+			met[key] = true
+			colors[p.White]++
+			colors[p.Black]--
+		}
+		reportFavorites(t, s, round)
+	}
+	for p, diff := range colors {
+		if diff > 2 || diff < -2 {
+			t.Fail()
+			t.Logf("Player %d has a color difference of %d\n", p, diff)
+		}
+	}
+
+	standings := s.Standings()
+	for i, st := range standings {
+		if st.Player != i {
+			t.Fail()
+			t.Logf("Expected player %d in place %d, got player %d\n", i, i+1, st.Player)
+		}
+	}
+	if !almostEqual(standings[0].Score, 5) {
+		t.Fail()
+		t.Logf("Expected the top seed to win every round, got %f\n", standings[0].Score)
+	}
+	if s.Rounds() != 5 || len(s.Round(1)) != 4 || s.Round(6) != nil {
+		t.Fail()
+		t.Log("Unexpected rounds")
+	}
+}
+
+func TestSwissByes(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	s := c.NewSwiss(swissPlayers(5)...)
+
+	byes := make(map[int]bool)
+	for r := 0; r < 3; r++ {
+		round, err := s.Pair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		bye := round[len(round)-1]
+		if bye.Black != -1 || byes[bye.White] {
+			t.Fail()
+			t.Logf("Round %d: expected a new player to get the bye, got %+v\n", r+1, bye)
+		}
+		byes[bye.White] = true
+		if r == 0 && bye.White != 4 {
+			t.Fail()
+			t.Logf("Expected the lowest rated player to get the first bye, got %d\n", bye.White)
+		}
+		reportFavorites(t, s, round)
+	}
+	// byes score a win
+	for _, st := range s.Standings() {
+		if st.Player == 4 && !almostEqual(st.Score, 1) {
+			t.Fail()
+			t.Logf("Expected the bye to score 1, got %f\n", st.Score)
+		}
+	}
+}
+
+func TestSwissColorRules(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+	s := c.NewSwiss(swissPlayers(7)...)
+
+	// results of the boards of the first five rounds, byes excluded
+	results := [][]elo.MatchOutcome{
+		{elo.OutcomePlayerOneWin, elo.OutcomeDraw, elo.OutcomeDraw},
+		{elo.OutcomePlayerTwoWin, elo.OutcomePlayerTwoWin, elo.OutcomePlayerTwoWin},
+		{elo.OutcomePlayerOneWin, elo.OutcomeDraw, elo.OutcomePlayerOneWin},
+		{elo.OutcomeDraw, elo.OutcomePlayerTwoWin, elo.OutcomeDraw},
+		{elo.OutcomeDraw, elo.OutcomePlayerOneWin, elo.OutcomePlayerTwoWin},
+	}
+	colors := make([][]int, 7)
+	for r := 0; r <= len(results); r++ {
+		round, err := s.Pair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		board := 0
+		for _, p := range round {
+			if p.Black < 0 {
+				continue
+			}
+			colors[p.White] = append(colors[p.White], 1)
+			colors[p.Black] = append(colors[p.Black], -1)
+			if r < len(results) {
+				s.Report(p.Board, &elo.MatchResult{Outcome: results[r][board]})
+			}
+			board++
+		}
+	}
+
+	// in the last round, player 1 with -1, -1, 1, 1, -1 plays player 2 with 1, 1, -1, -1,
+	// and only player 2 taking white avoids three of the same color in a row
+	for p, h := range colors {
+		n := len(h)
+		if n >= 3 && h[n-1] == h[n-2] && h[n-2] == h[n-3] {
+			t.Fail()
+			t.Logf("Expected no player to have the same color three times in a row, got %d with %v\n", p, h)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package elo
+
+import (
+	"math"
+)
+
+// Combines the ratings of a team's players into a single team rating. weights holds
+// how much of the match each player took part in, and has the same length as ratings.
+type TeamAggregator func(ratings, weights []float64) float64
+
+// Splits a change in team rating between the team's players, returning each player's
+// change in the same order as ratings. weights holds how much of the match each player
+// took part in, and has the same length as ratings.
+type TeamDistributor func(delta float64, ratings, weights []float64) []float64
+
+// Rates a team as the average of its players.
+func TeamMean(ratings, weights []float64) float64 {
+	return TeamSum(ratings, weights) / float64(len(ratings))
+}
+
+// Rates a team as the sum of its players.
+func TeamSum(ratings, weights []float64) float64 {
+	var sum float64
+	for _, r := range ratings {
+		sum += r
+	}
+	return sum
+}
+
+// Rates a team as its best player.
+func TeamMax(ratings, weights []float64) float64 {
+	max := math.Inf(-1)
+	for _, r := range ratings {
+		max = math.Max(max, r)
+	}
+	return max
+}
+
+// Rates a team as the root mean square of its players, which favours
+// teams with a strong player over evenly matched teams.
+func TeamRMS(ratings, weights []float64) float64 {
+	var sum float64
+	for _, r := range ratings {
+		sum += r * r
+	}
+	return math.Sqrt(sum / float64(len(ratings)))
+}
+
+// Rates a team as the average of its players, weighted by how much of the
+// match each player took part in.
+func TeamWeightedMean(ratings, weights []float64) float64 {
+	var sum, total float64
+	for i, r := range ratings {
+		sum += r * weights[i]
+		total += weights[i]
+	}
+	if total == 0 {
+		return TeamMean(ratings, weights)
+	}
+	return sum / total
+}
+
+// Gives every player the team's change, scaled by how much of the match they took part in.
+func DistributeEqual(delta float64, ratings, weights []float64) []float64 {
+	deltas := make([]float64, len(ratings))
+	for i := range ratings {
+		deltas[i] = delta * weights[i]
+	}
+	return deltas
+}
+
+// Splits the team's change in proportion to each player's contribution, which is
+// their rating scaled by how much of the match they took part in. Higher rated
+// players gain and lose more.
+func DistributeProportional(delta float64, ratings, weights []float64) []float64 {
+	shares := make([]float64, len(ratings))
+	for i, r := range ratings {
+		shares[i] = r * weights[i]
+	}
+	return distributeShares(delta, shares)
+}
+
+// Splits the team's change in inverse proportion to each player's rating, scaled by
+// how much of the match they took part in. Lower rated players gain and lose more.
+func DistributeInverse(delta float64, ratings, weights []float64) []float64 {
+	shares := make([]float64, len(ratings))
+	for i, r := range ratings {
+		// avoid dividing by zero for players rated at or below 0
+		shares[i] = weights[i] / math.Max(r, 1)
+	}
+	return distributeShares(delta, shares)
+}
+
+// Scales the shares so that the average player's change equals delta.
+func distributeShares(delta float64, shares []float64) []float64 {
+	var total float64
+	for _, s := range shares {
+		total += s
+	}
+	deltas := make([]float64, len(shares))
+	if total == 0 {
+		return deltas
+	}
+	for i, s := range shares {
+		deltas[i] = delta * s * float64(len(shares)) / total
+	}
+	return deltas
+}
+
+// Set how team ratings are calculated from their players. Default is elo.TeamMean.
+func (b *CalculatorBuilder) WithTeamAggregator(a TeamAggregator) *CalculatorBuilder {
+	b.c.teamAggregator = a
+	return b
+}
+
+// Set how a team's change in rating is split between its players.
+// Default is elo.DistributeEqual.
+func (b *CalculatorBuilder) WithTeamDistributor(d TeamDistributor) *CalculatorBuilder {
+	b.c.teamDistributor = d
+	return b
+}
+
+// A match between two teams of players. Each team is rated as a single player using
+// the calculator's TeamAggregator, and the change in each team's rating is split
+// between its players using the calculator's TeamDistributor.
+type TeamMatch struct {
+	TeamOne []Player
+	TeamTwo []Player
+
+	// Optional. How much of the match, between 0 and 1, each player of team one took part in.
+	TeamOneWeights []float64
+	// Optional. How much of the match, between 0 and 1, each player of team two took part in.
+	TeamTwoWeights []float64
+
+	finished bool
+	c        Calculator
+}
+
+// Args t1 and t2 should be non-empty and contain non-nil pointers.
+func (c *Calculator) NewTeamMatch(t1, t2 []Player) *TeamMatch {
+	m := new(TeamMatch)
+	m.TeamOne = t1
+	m.TeamTwo = t2
+	m.c = *c
+	return m
+}
+
+// Set a strategy to be used for this match only.
+func (m *TeamMatch) SetStrategy(sf StrategyFunc) {
+	m.c.strategy = sf
+}
+
+// K must be non-negative. If a negative value is provided, K will be unchanged.
+// Setting K for a match overrides the calculator's KFactorFunc.
+func (m *TeamMatch) SetKValue(k float64) {
+	if k < 0 {
+		return
+	}
+	m.c.k = k
+	m.c.kFactor = nil
+}
+func (m *TeamMatch) GetKValue() float64 {
+	return m.c.k
+}
+
+// Set a team aggregator to be used for this match only.
+func (m *TeamMatch) SetAggregator(a TeamAggregator) {
+	m.c.teamAggregator = a
+}
+
+// Set a team distributor to be used for this match only.
+func (m *TeamMatch) SetDistributor(d TeamDistributor) {
+	m.c.teamDistributor = d
+}
+
+// Set an advantage in elo for team one, such as home field, to be used for this
+// match only. A negative value favors team two.
+func (m *TeamMatch) SetAdvantage(a float64) {
+	m.c.advantage = a
+}
+func (m *TeamMatch) GetAdvantage() float64 {
+	return m.c.advantage
+}
+
+// Returns the team ratings as calculated by the match's TeamAggregator.
+func (m *TeamMatch) TeamRatings() (float64, float64) {
+	r1, w1 := teamRatings(m.TeamOne, m.TeamOneWeights)
+	r2, w2 := teamRatings(m.TeamTwo, m.TeamTwoWeights)
+	return m.c.teamAggregator(r1, w1), m.c.teamAggregator(r2, w2)
+}
+
+func (m *TeamMatch) GetOdds() *MatchOdds {
+	t1, t2 := m.TeamRatings()
+	return davidsonOdds(t1+m.c.advantage, t2, m.c.deviation, m.c.drawParameter)
+}
+
+// Adjusts every player's elo according to which team won the match.
+// Can only be called once. Any subsequent calls on the same match will result in no changes
+// to the players' elo ratings.
+func (m *TeamMatch) Play(result *MatchResult) {
+	if m.finished ||
+		((result.Outcome == OutcomeDraw) &&
+			m.c.ignoreDraws &&
+			(result.PlayerOneScore == result.PlayerTwoScore)) {
+		return
+	}
+	r1, w1 := teamRatings(m.TeamOne, m.TeamOneWeights)
+	r2, w2 := teamRatings(m.TeamTwo, m.TeamTwoWeights)
+	t1, t2 := m.c.teamAggregator(r1, w1), m.c.teamAggregator(r2, w2)
+
+	n1, n2 := m.c.Calculate(t1, t2, result)
+
+	for i, d := range m.c.teamDistributor(n1-t1, r1, w1) {
+		m.TeamOne[i].SetElo(r1[i] + d*m.kScale(m.TeamOne[i]))
+	}
+	for i, d := range m.c.teamDistributor(n2-t2, r2, w2) {
+		m.TeamTwo[i].SetElo(r2[i] + d*m.kScale(m.TeamTwo[i]))
+	}
+	m.finished = true
+}
+
+// Returns how much to scale the player's share of their team's change by, which is their
+// own K-Value over the match's K-Value, or 1 if they do not have their own.
+func (m *TeamMatch) kScale(p Player) float64 {
+	k := playerK(m.c.kFactor, p)
+	if k <= 0 || m.c.k <= 0 {
+		return 1
+	}
+	return k / m.c.k
+}
+
+// Returns the players' ratings, and their weights or 1 if no weights were given.
+func teamRatings(team []Player, weights []float64) ([]float64, []float64) {
+	ratings := make([]float64, len(team))
+	w := make([]float64, len(team))
+	for i, p := range team {
+		ratings[i] = p.GetElo()
+		w[i] = 1
+		if i < len(weights) {
+			w[i] = weights[i]
+		}
+	}
+	return ratings, w
+}
@@ -0,0 +1,148 @@
+package elo_test
+
+import (
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+func TestTeamMatch(t *testing.T) {
+	c := elo.NewCalculatorBuilder().Build()
+
+	t1 := []elo.Player{&player{1700}, &player{1500}}
+	t2 := []elo.Player{&player{1800}, &player{1800}}
+
+	m := c.NewTeamMatch(t1, t2)
+	r1, r2 := m.TeamRatings()
+	if !almostEqual(r1, 1600) || !almostEqual(r2, 1800) {
+		t.Fail()
+		t.Logf("Expected team ratings %f and %f, got %f and %f\n", 1600.0, 1800.0, r1, r2)
+	}
+
+	o := m.GetOdds()
+	if !almostEqual(o.PlayerOneOdds, 0.240253) {
+		t.Fail()
+		t.Logf("Expected team one odds to be %f, got %f\n", 0.240253, o.PlayerOneOdds)
+	}
+
+	m.Play(&elo.MatchResult{
+		Outcome: elo.OutcomePlayerTwoWin,
+	})
+
+	// same change as a single 1600 vs 1800 match
+	expected := []float64{1692.311902, 1492.311902, 1807.688098, 1807.688098}
+	for i, p := range append(t1, t2...) {
+		if !almostEqual(p.GetElo(), expected[i]) {
+			t.Fail()
+			t.Logf("Expected player %d Elo %f, got %f\n", i, expected[i], p.GetElo())
+		}
+	}
+
+	// a second call does nothing
+	m.Play(&elo.MatchResult{
+		Outcome: elo.OutcomePlayerOneWin,
+	})
+	if !almostEqual(t1[0].GetElo(), 1692.311902) {
+		t.Fail()
+		t.Log("The same matched played twice should not alter elo twice.")
+	}
+}
+
+func TestTeamAggregators(t *testing.T) {
+	ratings := []float64{1000, 2000}
+	weights := []float64{1, 0.5}
+
+	tests := []struct {
+		name     string
+		agg      elo.TeamAggregator
+		expected float64
+	}{
+		{"mean", elo.TeamMean, 1500},
+		{"sum", elo.TeamSum, 3000},
+		{"max", elo.TeamMax, 2000},
+		{"rms", elo.TeamRMS, 1581.138830},
+		{"weighted mean", elo.TeamWeightedMean, 1333.333333},
+	}
+	for _, test := range tests {
+		if r := test.agg(ratings, weights); !almostEqual(r, test.expected) {
+			t.Fail()
+			t.Logf("Expected %s rating %f, got %f\n", test.name, test.expected, r)
+		}
+	}
+}
+
+func TestTeamDistributors(t *testing.T) {
+	ratings := []float64{1000, 2000}
+	weights := []float64{1, 1}
+
+	d := elo.DistributeEqual(10, ratings, weights)
+	if !almostEqual(d[0], 10) || !almostEqual(d[1], 10) {
+		t.Fail()
+		t.Logf("Expected equal changes of %f, got %v\n", 10.0, d)
+	}
+
+	d = elo.DistributeProportional(10, ratings, weights)
+	if !almostEqual(d[0], 6.666667) || !almostEqual(d[1], 13.333333) {
+		t.Fail()
+		t.Logf("Expected proportional changes of %f and %f, got %v\n", 6.666667, 13.333333, d)
+	}
+
+	d = elo.DistributeInverse(10, ratings, weights)
+	if !almostEqual(d[0], 13.333333) || !almostEqual(d[1], 6.666667) {
+		t.Fail()
+		t.Logf("Expected inverse changes of %f and %f, got %v\n", 13.333333, 6.666667, d)
+	}
+
+	d = elo.DistributeEqual(10, ratings, []float64{1, 0})
+	if !almostEqual(d[1], 0) {
+		t.Fail()
+		t.Logf("A player who did not take part must not change, got %f\n", d[1])
+	}
+}
+
+func TestTeamMatchOverrides(t *testing.T) {
+	c := elo.NewCalculatorBuilder().
+		WithTeamAggregator(elo.TeamSum).
+		WithTeamDistributor(elo.DistributeProportional).
+		Build()
+
+	t1 := []elo.Player{&player{1000}, &player{1000}}
+	t2 := []elo.Player{&player{1000}, &player{1000}}
+
+	m := c.NewTeamMatch(t1, t2)
+	m.SetAggregator(elo.TeamMean)
+	m.SetDistributor(elo.DistributeEqual)
+	m.SetKValue(20)
+	m.SetKValue(-1) // will be ignored
+	m.TeamOneWeights = []float64{1, 0.5}
+	m.Play(&elo.MatchResult{
+		Outcome: elo.OutcomePlayerOneWin,
+	})
+
+	if !almostEqual(m.GetKValue(), 20) {
+		t.Fail()
+		t.Log("Failed to override K value.")
+	}
+	if !almostEqual(t1[0].GetElo(), 1010) || !almostEqual(t1[1].GetElo(), 1005) {
+		t.Fail()
+		t.Logf("Expected %f and %f, got %f and %f\n", 1010.0, 1005.0, t1[0].GetElo(), t1[1].GetElo())
+	}
+	if !almostEqual(t2[0].GetElo(), 990) {
+		t.Fail()
+		t.Logf("Expected %f, got %f\n", 990.0, t2[0].GetElo())
+	}
+
+	// ignored draws
+	c = elo.NewCalculatorBuilder().WithIgnoreDraws().Build()
+	m = c.NewTeamMatch(t1, t2)
+	m.SetStrategy(func(input *elo.CalculatorInput) (float64, float64) {
+		return 0, 0
+	})
+	m.Play(&elo.MatchResult{
+		Outcome: elo.OutcomeDraw,
+	})
+	if !almostEqual(t1[0].GetElo(), 1010) {
+		t.Fail()
+		t.Logf("Draw not ignored, got %f\n", t1[0].GetElo())
+	}
+}
@@ -0,0 +1,540 @@
+package elo
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+var (
+	ErrTooFewTeams    = errors.New("elo: at least two teams are required")
+	ErrEmptyTeam      = errors.New("elo: teams must have at least one player")
+	ErrRankMismatch   = errors.New("elo: number of ranks must match number of teams")
+	ErrWeightMismatch = errors.New("elo: weights must match the shape of the teams")
+)
+
+// A Bayesian skill rating, where Mu is the estimated skill and Sigma is
+// the uncertainty of that estimate.
+type SkillRating struct {
+	Mu    float64
+	Sigma float64
+}
+
+// Returns a conservative estimate of the skill, which the player's true skill
+// is very likely to be above.
+func (r SkillRating) Conservative() float64 {
+	return r.Mu - 3*r.Sigma
+}
+
+// A SkillPlayer is a player rated with a SkillRating, such as by TrueSkill.
+type SkillPlayer interface {
+	GetMu() float64
+	SetMu(float64)
+	GetSigma() float64
+	SetSigma(float64)
+}
+
+type TrueSkillCalculatorBuilder struct {
+	c TrueSkillCalculator
+}
+
+// Rates teams of any size using TrueSkill. Each match is modelled as a factor graph,
+// which is solved by passing messages between the players' skills, their performances,
+// their teams' performances and the differences between teams of adjacent rank.
+type TrueSkillCalculator struct {
+	mu              float64
+	sigma           float64
+	beta            float64
+	tau             float64
+	drawProbability float64
+}
+
+func NewTrueSkillCalculatorBuilder() *TrueSkillCalculatorBuilder {
+	return &TrueSkillCalculatorBuilder{
+		c: TrueSkillCalculator{
+			mu:              25,
+			sigma:           25.0 / 3,
+			beta:            25.0 / 6,
+			tau:             25.0 / 300,
+			drawProbability: 0.1,
+		}}
+}
+
+// Set the skill of a new player. Default is 25.
+func (b *TrueSkillCalculatorBuilder) WithMu(mu float64) *TrueSkillCalculatorBuilder {
+	b.c.mu = mu
+	return b
+}
+
+// Set the uncertainty of a new player's skill. Must be greater than 0. Providing
+// a non-positive value will result in no change. Default is 25/3.
+func (b *TrueSkillCalculatorBuilder) WithSigma(sigma float64) *TrueSkillCalculatorBuilder {
+	if sigma <= 0 {
+		return b
+	}
+	b.c.sigma = sigma
+	return b
+}
+
+// Set the skill difference that gives the better player about a 76% chance to win.
+// Must be greater than 0. Providing a non-positive value will result in no change.
+// Default is 25/6.
+func (b *TrueSkillCalculatorBuilder) WithBeta(beta float64) *TrueSkillCalculatorBuilder {
+	if beta <= 0 {
+		return b
+	}
+	b.c.beta = beta
+	return b
+}
+
+// Set the dynamic factor, which is added to every player's uncertainty before a match
+// so that skills can keep changing. Must be non-negative. Providing a negative value
+// will result in no change. Default is 25/300.
+func (b *TrueSkillCalculatorBuilder) WithTau(tau float64) *TrueSkillCalculatorBuilder {
+	if tau < 0 {
+		return b
+	}
+	b.c.tau = tau
+	return b
+}
+
+// Set the probability of a draw between two evenly matched players. Must be between
+// 0 and 1. Providing any other value will result in no change. Default is 0.1.
+func (b *TrueSkillCalculatorBuilder) WithDrawProbability(p float64) *TrueSkillCalculatorBuilder {
+	if p < 0 || p >= 1 {
+		return b
+	}
+	b.c.drawProbability = p
+	return b
+}
+
+// Returns a TrueSkillCalculator reference using the settings defined by the builder.
+func (b *TrueSkillCalculatorBuilder) Build() *TrueSkillCalculator {
+	return &b.c
+}
+
+// Returns the rating given to a new player.
+func (c *TrueSkillCalculator) NewRating() SkillRating {
+	return SkillRating{Mu: c.mu, Sigma: c.sigma}
+}
+
+// Calculates new ratings for every player after a match between the given teams.
+// ranks holds each team's finishing position, where a lower rank is better and equal
+// ranks are a draw. weights is optional, and holds the fraction of the match, between
+// 0 and 1, each player took part in. The new ratings are returned in the same shape
+// as teams.
+func (c *TrueSkillCalculator) Rate(teams [][]SkillRating, ranks []int, weights [][]float64) ([][]SkillRating, error) {
+	if err := validateTeams(teams, ranks, weights); err != nil {
+		return nil, err
+	}
+
+	// sort teams from best to worst rank
+	order := make([]int, len(teams))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return ranks[order[i]] < ranks[order[j]]
+	})
+
+	g := c.buildGraph(teams, ranks, weights, order)
+	g.run()
+
+	result := make([][]SkillRating, len(teams))
+	for t, team := range order {
+		result[team] = make([]SkillRating, len(teams[team]))
+		for i, v := range g.skills[t] {
+			result[team][i] = SkillRating{Mu: v.value.mu(), Sigma: v.value.sigma()}
+		}
+	}
+	return result, nil
+}
+
+// Rates the players in place. See Rate.
+func (c *TrueSkillCalculator) RatePlayers(teams [][]SkillPlayer, ranks []int, weights [][]float64) error {
+	ratings := skillRatings(teams)
+	result, err := c.Rate(ratings, ranks, weights)
+	if err != nil {
+		return err
+	}
+	setSkillRatings(teams, result)
+	return nil
+}
+
+func validateTeams(teams [][]SkillRating, ranks []int, weights [][]float64) error {
+	if len(teams) < 2 {
+		return ErrTooFewTeams
+	}
+	if len(ranks) != len(teams) {
+		return ErrRankMismatch
+	}
+	if weights != nil && len(weights) != len(teams) {
+		return ErrWeightMismatch
+	}
+	for i, team := range teams {
+		if len(team) == 0 {
+			return ErrEmptyTeam
+		}
+		if weights != nil && len(weights[i]) != len(team) {
+			return ErrWeightMismatch
+		}
+	}
+	return nil
+}
+
+func skillRatings(teams [][]SkillPlayer) [][]SkillRating {
+	ratings := make([][]SkillRating, len(teams))
+	for i, team := range teams {
+		ratings[i] = make([]SkillRating, len(team))
+		for j, p := range team {
+			ratings[i][j] = SkillRating{Mu: p.GetMu(), Sigma: p.GetSigma()}
+		}
+	}
+	return ratings
+}
+
+func setSkillRatings(teams [][]SkillPlayer, ratings [][]SkillRating) {
+	for i, team := range teams {
+		for j, p := range team {
+			p.SetMu(ratings[i][j].Mu)
+			p.SetSigma(ratings[i][j].Sigma)
+		}
+	}
+}
+
+// Returns the performance difference below which two teams with the given
+// total number of players are considered to have drawn.
+func (c *TrueSkillCalculator) drawMargin(players int) float64 {
+	return normalPPF((c.drawProbability+1)/2) * math.Sqrt(float64(players)) * c.beta
+}
+
+const (
+	// Iteration stops once no message changes by more than this.
+	trueSkillMinDelta = 0.0001
+
+	// Upper bound on schedule iterations for matches with more than two teams.
+	trueSkillMaxIterations = 10
+)
+
+// A normal distribution stored by its precision (pi) and precision adjusted
+// mean (tau), which makes multiplying and dividing distributions cheap.
+type gaussian struct {
+	pi  float64
+	tau float64
+}
+
+func newGaussian(mu, sigma float64) gaussian {
+	pi := 1 / (sigma * sigma)
+	return gaussian{pi: pi, tau: pi * mu}
+}
+
+func (g gaussian) mu() float64 {
+	if g.pi == 0 {
+		return 0
+	}
+	return g.tau / g.pi
+}
+
+func (g gaussian) sigma() float64 {
+	if g.pi == 0 {
+		return math.Inf(1)
+	}
+	return math.Sqrt(1 / g.pi)
+}
+
+func (g gaussian) mul(o gaussian) gaussian {
+	return gaussian{pi: g.pi + o.pi, tau: g.tau + o.tau}
+}
+
+func (g gaussian) div(o gaussian) gaussian {
+	return gaussian{pi: g.pi - o.pi, tau: g.tau - o.tau}
+}
+
+// A variable node of the factor graph. Its value is the product of every
+// message sent to it.
+type tsVariable struct {
+	value gaussian
+}
+
+// Connects a factor to a variable, and remembers the last message the
+// factor sent along it.
+type tsEdge struct {
+	v   *tsVariable
+	msg gaussian
+}
+
+func (e *tsEdge) incoming() gaussian {
+	return e.v.value.div(e.msg)
+}
+
+// Replaces this edge's message to the variable and returns how much the
+// variable changed.
+func (e *tsEdge) send(msg gaussian) float64 {
+	old := e.v.value
+	e.v.value = old.div(e.msg).mul(msg)
+	e.msg = msg
+	return gaussianDelta(old, e.v.value)
+}
+
+// Sets the variable to the given value, adjusting this edge's message to match.
+func (e *tsEdge) set(value gaussian) float64 {
+	return e.send(value.mul(e.msg).div(e.v.value))
+}
+
+func gaussianDelta(a, b gaussian) float64 {
+	piDelta := math.Abs(a.pi - b.pi)
+	if math.IsInf(piDelta, 0) {
+		return 0
+	}
+	return math.Max(math.Abs(a.tau-b.tau), math.Sqrt(piDelta))
+}
+
+// Connects a skill to a performance, adding the performance variance beta^2.
+type tsLikelihood struct {
+	skill    *tsEdge
+	perf     *tsEdge
+	variance float64
+}
+
+func (f *tsLikelihood) down() float64 {
+	msg := f.skill.incoming()
+	a := 1 / (1 + f.variance*msg.pi)
+	return f.perf.send(gaussian{pi: a * msg.pi, tau: a * msg.tau})
+}
+
+func (f *tsLikelihood) up() float64 {
+	msg := f.perf.incoming()
+	a := 1 / (1 + f.variance*msg.pi)
+	return f.skill.send(gaussian{pi: a * msg.pi, tau: a * msg.tau})
+}
+
+// Constrains sum to be the weighted sum of terms.
+type tsSum struct {
+	sum    *tsEdge
+	terms  []*tsEdge
+	coeffs []float64
+}
+
+func (f *tsSum) down() float64 {
+	return f.update(f.sum, f.terms, f.coeffs)
+}
+
+// Sends a message to the term at index i, by solving the sum for it.
+func (f *tsSum) up(i int) float64 {
+	coeff := f.coeffs[i]
+	edges := make([]*tsEdge, len(f.terms))
+	coeffs := make([]float64, len(f.terms))
+	for j, c := range f.coeffs {
+		if j == i {
+			edges[j] = f.sum
+			coeffs[j] = 1 / coeff
+		} else {
+			edges[j] = f.terms[j]
+			coeffs[j] = -c / coeff
+		}
+	}
+	return f.update(f.terms[i], edges, coeffs)
+}
+
+func (f *tsSum) update(target *tsEdge, edges []*tsEdge, coeffs []float64) float64 {
+	var piInv, mu float64
+	for i, e := range edges {
+		if coeffs[i] == 0 {
+			continue
+		}
+		msg := e.incoming()
+		mu += coeffs[i] * msg.mu()
+		piInv += coeffs[i] * coeffs[i] / msg.pi
+	}
+	pi := 1 / piInv
+	return target.send(gaussian{pi: pi, tau: pi * mu})
+}
+
+// Truncates a team performance difference according to the match outcome.
+type tsTruncate struct {
+	diff       *tsEdge
+	drawMargin float64
+	draw       bool
+}
+
+func (f *tsTruncate) up() float64 {
+	msg := f.diff.incoming()
+	sqrtPi := math.Sqrt(msg.pi)
+	t := msg.tau / sqrtPi
+	e := f.drawMargin * sqrtPi
+	var v, w float64
+	if f.draw {
+		v, w = vDraw(t, e), wDraw(t, e)
+	} else {
+		v, w = vWin(t, e), wWin(t, e)
+	}
+	denom := 1 - w
+	return f.diff.set(gaussian{
+		pi:  msg.pi / denom,
+		tau: (msg.tau + sqrtPi*v) / denom,
+	})
+}
+
+type tsGraph struct {
+	skills      [][]*tsVariable
+	priors      [][]gaussian
+	priorEdges  [][]*tsEdge
+	likelihoods [][]*tsLikelihood
+	teamPerfs   []*tsSum
+	teamDiffs   []*tsSum
+	truncates   []*tsTruncate
+}
+
+func (c *TrueSkillCalculator) buildGraph(teams [][]SkillRating, ranks []int, weights [][]float64, order []int) *tsGraph {
+	g := new(tsGraph)
+	n := len(order)
+	teamVars := make([]*tsVariable, n)
+	g.skills = make([][]*tsVariable, n)
+	g.priors = make([][]gaussian, n)
+	g.priorEdges = make([][]*tsEdge, n)
+	g.likelihoods = make([][]*tsLikelihood, n)
+	g.teamPerfs = make([]*tsSum, n)
+
+	for t, team := range order {
+		sum := &tsSum{sum: &tsEdge{v: new(tsVariable)}}
+		teamVars[t] = sum.sum.v
+		for i, r := range teams[team] {
+			skill := new(tsVariable)
+			perf := new(tsVariable)
+			g.skills[t] = append(g.skills[t], skill)
+			g.priors[t] = append(g.priors[t], newGaussian(r.Mu, math.Sqrt(r.Sigma*r.Sigma+c.tau*c.tau)))
+			g.priorEdges[t] = append(g.priorEdges[t], &tsEdge{v: skill})
+			g.likelihoods[t] = append(g.likelihoods[t], &tsLikelihood{
+				skill:    &tsEdge{v: skill},
+				perf:     &tsEdge{v: perf},
+				variance: c.beta * c.beta,
+			})
+
+			w := 1.0
+			if weights != nil {
+				// a weight of exactly zero would make the sum factor unsolvable
+				w = math.Max(weights[team][i], trueSkillMinDelta)
+			}
+			sum.terms = append(sum.terms, &tsEdge{v: perf})
+			sum.coeffs = append(sum.coeffs, w)
+		}
+		g.teamPerfs[t] = sum
+	}
+
+	for t := 0; t < n-1; t++ {
+		diff := new(tsVariable)
+		g.teamDiffs = append(g.teamDiffs, &tsSum{
+			sum:    &tsEdge{v: diff},
+			terms:  []*tsEdge{{v: teamVars[t]}, {v: teamVars[t+1]}},
+			coeffs: []float64{1, -1},
+		})
+		players := len(teams[order[t]]) + len(teams[order[t+1]])
+		g.truncates = append(g.truncates, &tsTruncate{
+			diff:       &tsEdge{v: diff},
+			drawMargin: c.drawMargin(players),
+			draw:       ranks[order[t]] == ranks[order[t+1]],
+		})
+	}
+	return g
+}
+
+// Runs the message passing schedule, leaving the posterior skills in g.skills.
+func (g *tsGraph) run() {
+	for t := range g.skills {
+		for i := range g.skills[t] {
+			g.priorEdges[t][i].send(g.priors[t][i])
+			g.likelihoods[t][i].down()
+		}
+		g.teamPerfs[t].down()
+	}
+
+	last := len(g.teamDiffs) - 1
+	for iter := 0; iter < trueSkillMaxIterations; iter++ {
+		var delta float64
+		if last == 0 {
+			g.teamDiffs[0].down()
+			delta = g.truncates[0].up()
+		} else {
+			for d := 0; d < last; d++ {
+				g.teamDiffs[d].down()
+				delta = math.Max(delta, g.truncates[d].up())
+				g.teamDiffs[d].up(1)
+			}
+			for d := last; d > 0; d-- {
+				g.teamDiffs[d].down()
+				delta = math.Max(delta, g.truncates[d].up())
+				g.teamDiffs[d].up(0)
+			}
+		}
+		if delta <= trueSkillMinDelta {
+			break
+		}
+	}
+	g.teamDiffs[0].up(0)
+	g.teamDiffs[last].up(1)
+
+	for t := range g.skills {
+		for i := range g.teamPerfs[t].terms {
+			g.teamPerfs[t].up(i)
+		}
+		for _, l := range g.likelihoods[t] {
+			l.up()
+		}
+	}
+}
+
+func normalPDF(x float64) float64 {
+	return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+}
+
+func normalCDF(x float64) float64 {
+	return math.Erfc(-x/math.Sqrt2) / 2
+}
+
+func normalPPF(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
+
+// The additive and multiplicative corrections to a performance difference t
+// when the winner is known, given the draw margin e.
+func vWin(t, e float64) float64 {
+	x := t - e
+	denom := normalCDF(x)
+	if denom == 0 {
+		return -x
+	}
+	return normalPDF(x) / denom
+}
+
+func wWin(t, e float64) float64 {
+	x := t - e
+	v := vWin(t, e)
+	return v * (v + x)
+}
+
+// The additive and multiplicative corrections to a performance difference t
+// when the match was drawn, given the draw margin e.
+func vDraw(t, e float64) float64 {
+	abs := math.Abs(t)
+	a, b := e-abs, -e-abs
+	denom := normalCDF(a) - normalCDF(b)
+	v := a
+	if denom != 0 {
+		v = (normalPDF(b) - normalPDF(a)) / denom
+	}
+	if t < 0 {
+		return -v
+	}
+	return v
+}
+
+func wDraw(t, e float64) float64 {
+	abs := math.Abs(t)
+	a, b := e-abs, -e-abs
+	denom := normalCDF(a) - normalCDF(b)
+	if denom == 0 {
+		return 1
+	}
+	v := vDraw(abs, e)
+	return v*v + (a*normalPDF(a)-b*normalPDF(b))/denom
+}
@@ -0,0 +1,178 @@
+package elo_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+type skillPlayer struct {
+	mu    float64
+	sigma float64
+}
+
+func (p *skillPlayer) GetMu() float64 {
+	return p.mu
+}
+func (p *skillPlayer) SetMu(mu float64) {
+	p.mu = mu
+}
+func (p *skillPlayer) GetSigma() float64 {
+	return p.sigma
+}
+func (p *skillPlayer) SetSigma(sigma float64) {
+	p.sigma = sigma
+}
+
+// TrueSkill results are usually published to three decimal places.
+func skillEqual(r elo.SkillRating, mu, sigma float64) bool {
+	return math.Abs(r.Mu-mu) < 0.001 && math.Abs(r.Sigma-sigma) < 0.001
+}
+
+func TestTrueSkillOneVsOne(t *testing.T) {
+	c := elo.NewTrueSkillCalculatorBuilder().Build()
+
+	r := c.NewRating()
+	n, err := c.Rate([][]elo.SkillRating{{r}, {r}}, []int{0, 1}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !skillEqual(n[0][0], 29.396, 7.171) {
+		t.Fail()
+		t.Logf("Expected winner %f/%f, got %f/%f\n", 29.396, 7.171, n[0][0].Mu, n[0][0].Sigma)
+	}
+	if !skillEqual(n[1][0], 20.604, 7.171) {
+		t.Fail()
+		t.Logf("Expected loser %f/%f, got %f/%f\n", 20.604, 7.171, n[1][0].Mu, n[1][0].Sigma)
+	}
+
+	// draw
+	n, err = c.Rate([][]elo.SkillRating{{r}, {r}}, []int{0, 0}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !skillEqual(n[0][0], 25.000, 6.458) || !skillEqual(n[1][0], 25.000, 6.458) {
+		t.Fail()
+		t.Logf("Expected both players %f/%f, got %f/%f and %f/%f\n", 25.0, 6.458,
+			n[0][0].Mu, n[0][0].Sigma, n[1][0].Mu, n[1][0].Sigma)
+	}
+
+	// ranks are not required to be in order
+	n, _ = c.Rate([][]elo.SkillRating{{r}, {r}}, []int{2, 1}, nil)
+	if !skillEqual(n[1][0], 29.396, 7.171) {
+		t.Fail()
+		t.Logf("Expected winner %f/%f, got %f/%f\n", 29.396, 7.171, n[1][0].Mu, n[1][0].Sigma)
+	}
+}
+
+func TestTrueSkillTeams(t *testing.T) {
+	c := elo.NewTrueSkillCalculatorBuilder().Build()
+
+	r := c.NewRating()
+	teams := [][]elo.SkillRating{{r, r}, {r}, {r, r, r}}
+	n, err := c.Rate(teams, []int{1, 2, 3}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n[0][0].Mu <= 25 || n[2][0].Mu >= 25 {
+		t.Fail()
+		t.Logf("First place must gain and last place must lose, got %f and %f\n", n[0][0].Mu, n[2][0].Mu)
+	}
+	for i, team := range n {
+		if len(team) != len(teams[i]) {
+			t.Fatalf("Expected team %d to have %d players, got %d\n", i, len(teams[i]), len(team))
+		}
+		for _, p := range team {
+			if p.Sigma >= r.Sigma {
+				t.Fail()
+				t.Logf("Uncertainty must shrink after a match, got %f\n", p.Sigma)
+			}
+		}
+	}
+	if !almostEqual(n[0][0].Mu, n[0][1].Mu) {
+		t.Fail()
+		t.Log("Identical teammates must receive identical ratings")
+	}
+
+	// one player beating a team of two
+	n, _ = c.Rate([][]elo.SkillRating{{r}, {r, r}}, []int{0, 1}, nil)
+	if !skillEqual(n[0][0], 33.731, 7.317) || !skillEqual(n[1][1], 16.269, 7.317) {
+		t.Fail()
+		t.Logf("Expected %f/%f and %f/%f, got %f/%f and %f/%f\n", 33.731, 7.317, 16.269, 7.317,
+			n[0][0].Mu, n[0][0].Sigma, n[1][1].Mu, n[1][1].Sigma)
+	}
+
+	// four player free-for-all
+	n, _ = c.Rate([][]elo.SkillRating{{r}, {r}, {r}, {r}}, []int{0, 1, 2, 3}, nil)
+	expected := []elo.SkillRating{{33.207, 6.348}, {27.401, 5.787}, {22.599, 5.787}, {16.793, 6.348}}
+	for i, e := range expected {
+		if !skillEqual(n[i][0], e.Mu, e.Sigma) {
+			t.Fail()
+			t.Logf("Expected place %d to be %f/%f, got %f/%f\n", i+1, e.Mu, e.Sigma, n[i][0].Mu, n[i][0].Sigma)
+		}
+	}
+
+	// a player who sat out most of the match moves less
+	n, err = c.Rate([][]elo.SkillRating{{r, r}, {r, r}}, []int{0, 1}, [][]float64{{1, 0.25}, {1, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n[0][1].Mu-25 >= n[0][0].Mu-25 {
+		t.Fail()
+		t.Logf("Partial player must gain less, got %f and %f\n", n[0][1].Mu, n[0][0].Mu)
+	}
+}
+
+func TestTrueSkillErrors(t *testing.T) {
+	c := elo.NewTrueSkillCalculatorBuilder().Build()
+	r := c.NewRating()
+
+	if _, err := c.Rate([][]elo.SkillRating{{r}}, []int{0}, nil); err != elo.ErrTooFewTeams {
+		t.Fail()
+		t.Logf("Expected %v, got %v\n", elo.ErrTooFewTeams, err)
+	}
+	if _, err := c.Rate([][]elo.SkillRating{{r}, {}}, []int{0, 1}, nil); err != elo.ErrEmptyTeam {
+		t.Fail()
+		t.Logf("Expected %v, got %v\n", elo.ErrEmptyTeam, err)
+	}
+	if _, err := c.Rate([][]elo.SkillRating{{r}, {r}}, []int{0}, nil); err != elo.ErrRankMismatch {
+		t.Fail()
+		t.Logf("Expected %v, got %v\n", elo.ErrRankMismatch, err)
+	}
+	if _, err := c.Rate([][]elo.SkillRating{{r}, {r}}, []int{0, 1}, [][]float64{{1}, {}}); err != elo.ErrWeightMismatch {
+		t.Fail()
+		t.Logf("Expected %v, got %v\n", elo.ErrWeightMismatch, err)
+	}
+}
+
+func TestTrueSkillPlayers(t *testing.T) {
+	c := elo.NewTrueSkillCalculatorBuilder().
+		WithMu(25).
+		WithSigma(25.0 / 3).
+		WithBeta(25.0 / 6).
+		WithTau(25.0 / 300).
+		WithDrawProbability(0.1).
+		WithSigma(-1).          // will be ignored
+		WithDrawProbability(2). // will be ignored
+		Build()
+
+	p1 := &skillPlayer{25, 25.0 / 3}
+	p2 := &skillPlayer{25, 25.0 / 3}
+
+	err := c.RatePlayers([][]elo.SkillPlayer{{p1}, {p2}}, []int{0, 1}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !skillEqual(elo.SkillRating{Mu: p1.mu, Sigma: p1.sigma}, 29.396, 7.171) {
+		t.Fail()
+		t.Logf("Expected winner %f/%f, got %f/%f\n", 29.396, 7.171, p1.mu, p1.sigma)
+	}
+	if !almostEqual(elo.SkillRating{Mu: 25, Sigma: 25.0 / 3}.Conservative(), 0) {
+		t.Fail()
+		t.Log("Conservative rating of a new player must be 0")
+	}
+}
@@ -0,0 +1,344 @@
+package elo
+
+import (
+	"math"
+	"sort"
+)
+
+// Selects which Weng-Lin approximation is used to rate a match.
+type WengLinModel int
+
+const (
+	// Ranks all teams at once, as in the Plackett-Luce model.
+	ModelPlackettLuce = WengLinModel(0)
+	// Compares every pair of teams using a logistic distribution.
+	ModelBradleyTerryFull = WengLinModel(1)
+	// Compares only teams of adjacent rank using a logistic distribution.
+	ModelBradleyTerryPart = WengLinModel(2)
+	// Compares every pair of teams using a normal distribution.
+	ModelThurstoneMostellerFull = WengLinModel(3)
+	// Compares only teams of adjacent rank using a normal distribution.
+	ModelThurstoneMostellerPart = WengLinModel(4)
+)
+
+type WengLinCalculatorBuilder struct {
+	c WengLinCalculator
+}
+
+// Rates teams of any size using the Bayesian approximations described by Weng and Lin
+// in "A Bayesian Approximation Method for Online Ranking", as popularised by OpenSkill.
+// Unlike TrueSkill, every update is a closed form expression.
+type WengLinCalculator struct {
+	mu      float64
+	sigma   float64
+	beta    float64
+	kappa   float64
+	epsilon float64
+	model   WengLinModel
+}
+
+func NewWengLinCalculatorBuilder() *WengLinCalculatorBuilder {
+	return &WengLinCalculatorBuilder{
+		c: WengLinCalculator{
+			mu:      25,
+			sigma:   25.0 / 3,
+			beta:    25.0 / 6,
+			kappa:   0.0001,
+			epsilon: 0.1,
+			model:   ModelPlackettLuce,
+		}}
+}
+
+// Set the model used to rate matches. Default is elo.ModelPlackettLuce.
+func (b *WengLinCalculatorBuilder) WithModel(m WengLinModel) *WengLinCalculatorBuilder {
+	b.c.model = m
+	return b
+}
+
+// Set the skill of a new player. Default is 25.
+func (b *WengLinCalculatorBuilder) WithMu(mu float64) *WengLinCalculatorBuilder {
+	b.c.mu = mu
+	return b
+}
+
+// Set the uncertainty of a new player's skill. Must be greater than 0. Providing
+// a non-positive value will result in no change. Default is 25/3.
+func (b *WengLinCalculatorBuilder) WithSigma(sigma float64) *WengLinCalculatorBuilder {
+	if sigma <= 0 {
+		return b
+	}
+	b.c.sigma = sigma
+	return b
+}
+
+// Set the variance of a player's performance around their skill. Must be greater
+// than 0. Providing a non-positive value will result in no change. Default is 25/6.
+func (b *WengLinCalculatorBuilder) WithBeta(beta float64) *WengLinCalculatorBuilder {
+	if beta <= 0 {
+		return b
+	}
+	b.c.beta = beta
+	return b
+}
+
+// Set the smallest fraction of a player's variance that can remain after a match,
+// which keeps sigma from collapsing to 0. Must be greater than 0. Providing a
+// non-positive value will result in no change. Default is 0.0001.
+func (b *WengLinCalculatorBuilder) WithKappa(kappa float64) *WengLinCalculatorBuilder {
+	if kappa <= 0 {
+		return b
+	}
+	b.c.kappa = kappa
+	return b
+}
+
+// Set the draw margin used by the Thurstone-Mosteller models. Must be non-negative.
+// Providing a negative value will result in no change. Default is 0.1.
+func (b *WengLinCalculatorBuilder) WithDrawMargin(e float64) *WengLinCalculatorBuilder {
+	if e < 0 {
+		return b
+	}
+	b.c.epsilon = e
+	return b
+}
+
+// Returns a WengLinCalculator reference using the settings defined by the builder.
+func (b *WengLinCalculatorBuilder) Build() *WengLinCalculator {
+	return &b.c
+}
+
+// Returns the rating given to a new player.
+func (c *WengLinCalculator) NewRating() SkillRating {
+	return SkillRating{Mu: c.mu, Sigma: c.sigma}
+}
+
+// Calculates new ratings for every player after a match between the given teams.
+// ranks holds each team's finishing position, where a lower rank is better and equal
+// ranks are a draw. If ranks is nil, teams are taken to be listed from first to last.
+// The new ratings are returned in the same shape as teams.
+func (c *WengLinCalculator) Rate(teams [][]SkillRating, ranks []int) ([][]SkillRating, error) {
+	if ranks == nil {
+		ranks = make([]int, len(teams))
+		for i := range ranks {
+			ranks[i] = i
+		}
+	}
+	if err := validateTeams(teams, ranks, nil); err != nil {
+		return nil, err
+	}
+
+	// sort teams from best to worst rank, so that adjacent teams are neighbours
+	order := make([]int, len(teams))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return ranks[order[i]] < ranks[order[j]]
+	})
+	sorted := make([]wengLinTeam, len(order))
+	for i, t := range order {
+		sorted[i] = newWengLinTeam(teams[t], ranks[t])
+	}
+
+	var omega, delta []float64
+	switch c.model {
+	case ModelBradleyTerryFull:
+		omega, delta = c.bradleyTerry(sorted, false)
+	case ModelBradleyTerryPart:
+		omega, delta = c.bradleyTerry(sorted, true)
+	case ModelThurstoneMostellerFull:
+		omega, delta = c.thurstoneMosteller(sorted, false)
+	case ModelThurstoneMostellerPart:
+		omega, delta = c.thurstoneMosteller(sorted, true)
+	default:
+		omega, delta = c.plackettLuce(sorted)
+	}
+
+	result := make([][]SkillRating, len(teams))
+	for i, t := range order {
+		result[t] = make([]SkillRating, len(teams[t]))
+		for j, p := range teams[t] {
+			share := p.Sigma * p.Sigma / sorted[i].sigmaSq
+			result[t][j] = SkillRating{
+				Mu:    p.Mu + share*omega[i],
+				Sigma: p.Sigma * math.Sqrt(math.Max(1-share*delta[i], c.kappa)),
+			}
+		}
+	}
+	return result, nil
+}
+
+// Rates the players in place. See Rate.
+func (c *WengLinCalculator) RatePlayers(teams [][]SkillPlayer, ranks []int) error {
+	result, err := c.Rate(skillRatings(teams), ranks)
+	if err != nil {
+		return err
+	}
+	setSkillRatings(teams, result)
+	return nil
+}
+
+// Returns each team's probability of winning a match between all of the given teams.
+// The probabilities sum to 1.
+func (c *WengLinCalculator) PredictWin(teams [][]SkillRating) []float64 {
+	n := len(teams)
+	odds := make([]float64, n)
+	if n < 2 {
+		for i := range odds {
+			odds[i] = 1
+		}
+		return odds
+	}
+	ts := make([]wengLinTeam, n)
+	for i, team := range teams {
+		ts[i] = newWengLinTeam(team, i)
+	}
+	pairs := float64(n*(n-1)) / 2
+	for i := range ts {
+		for q := range ts {
+			if i == q {
+				continue
+			}
+			diff := ts[i].mu - ts[q].mu
+			odds[i] += normalCDF(diff / math.Sqrt(float64(n)*c.beta*c.beta+ts[i].sigmaSq+ts[q].sigmaSq))
+		}
+		odds[i] /= pairs
+	}
+	return odds
+}
+
+// Returns each team's odds to win a match between two teams.
+func (c *WengLinCalculator) GetOdds(t1, t2 []SkillRating) *MatchOdds {
+	odds := c.PredictWin([][]SkillRating{t1, t2})
+	return &MatchOdds{
+		PlayerOneOdds: odds[0],
+		PlayerTwoOdds: odds[1],
+	}
+}
+
+type wengLinTeam struct {
+	mu      float64
+	sigmaSq float64
+	rank    int
+}
+
+func newWengLinTeam(team []SkillRating, rank int) wengLinTeam {
+	t := wengLinTeam{rank: rank}
+	for _, p := range team {
+		t.mu += p.Mu
+		t.sigmaSq += p.Sigma * p.Sigma
+	}
+	return t
+}
+
+// Returns the indexes of the teams team i is compared against.
+func opponents(n, i int, adjacent bool) []int {
+	var qs []int
+	for q := 0; q < n; q++ {
+		if q == i || (adjacent && q != i-1 && q != i+1) {
+			continue
+		}
+		qs = append(qs, q)
+	}
+	return qs
+}
+
+func (c *WengLinCalculator) bradleyTerry(teams []wengLinTeam, adjacent bool) (omega, delta []float64) {
+	omega = make([]float64, len(teams))
+	delta = make([]float64, len(teams))
+	for i, ti := range teams {
+		for _, q := range opponents(len(teams), i, adjacent) {
+			tq := teams[q]
+			ciq := math.Sqrt(ti.sigmaSq + tq.sigmaSq + 2*c.beta*c.beta)
+			piq := 1 / (1 + math.Exp((tq.mu-ti.mu)/ciq))
+			sigSqToCiq := ti.sigmaSq / ciq
+			gamma := math.Sqrt(ti.sigmaSq) / ciq
+
+			s := 0.0
+			if tq.rank > ti.rank {
+				s = 1
+			} else if tq.rank == ti.rank {
+				s = 0.5
+			}
+			omega[i] += sigSqToCiq * (s - piq)
+			delta[i] += gamma * sigSqToCiq / ciq * piq * (1 - piq)
+		}
+	}
+	return omega, delta
+}
+
+func (c *WengLinCalculator) thurstoneMosteller(teams []wengLinTeam, adjacent bool) (omega, delta []float64) {
+	omega = make([]float64, len(teams))
+	delta = make([]float64, len(teams))
+	for i, ti := range teams {
+		for _, q := range opponents(len(teams), i, adjacent) {
+			tq := teams[q]
+			ciq := math.Sqrt(ti.sigmaSq + tq.sigmaSq + 2*c.beta*c.beta)
+			deltaMu := (ti.mu - tq.mu) / ciq
+			sigSqToCiq := ti.sigmaSq / ciq
+			gamma := math.Sqrt(ti.sigmaSq) / ciq
+			e := c.epsilon / ciq
+
+			switch {
+			case tq.rank > ti.rank:
+				omega[i] += sigSqToCiq * vWin(deltaMu, e)
+				delta[i] += gamma * sigSqToCiq / ciq * wWin(deltaMu, e)
+			case tq.rank < ti.rank:
+				omega[i] -= sigSqToCiq * vWin(-deltaMu, e)
+				delta[i] += gamma * sigSqToCiq / ciq * wWin(-deltaMu, e)
+			default:
+				omega[i] += sigSqToCiq * vDraw(deltaMu, e)
+				delta[i] += gamma * sigSqToCiq / ciq * wDraw(deltaMu, e)
+			}
+		}
+	}
+	return omega, delta
+}
+
+func (c *WengLinCalculator) plackettLuce(teams []wengLinTeam) (omega, delta []float64) {
+	n := len(teams)
+	omega = make([]float64, n)
+	delta = make([]float64, n)
+
+	var cSq float64
+	for _, t := range teams {
+		cSq += t.sigmaSq + c.beta*c.beta
+	}
+	C := math.Sqrt(cSq)
+
+	// sumQ holds the strength of every team that finished at or below team q,
+	// and ties holds how many teams share team q's rank
+	sumQ := make([]float64, n)
+	ties := make([]float64, n)
+	for q, tq := range teams {
+		for _, ti := range teams {
+			if ti.rank >= tq.rank {
+				sumQ[q] += math.Exp(ti.mu / C)
+			}
+			if ti.rank == tq.rank {
+				ties[q]++
+			}
+		}
+	}
+
+	for i, ti := range teams {
+		strength := math.Exp(ti.mu / C)
+		var omegaSum, deltaSum float64
+		for q, tq := range teams {
+			if tq.rank > ti.rank {
+				continue
+			}
+			quotient := strength / sumQ[q]
+			if q == i {
+				omegaSum += (1 - quotient) / ties[q]
+			} else {
+				omegaSum -= quotient / ties[q]
+			}
+			deltaSum += quotient * (1 - quotient) / ties[q]
+		}
+		gamma := math.Sqrt(ti.sigmaSq) / C
+		omega[i] = ti.sigmaSq / C * omegaSum
+		delta[i] = gamma * ti.sigmaSq / cSq * deltaSum
+	}
+	return omega, delta
+}
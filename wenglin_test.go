@@ -0,0 +1,136 @@
+package elo_test
+
+import (
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+func TestWengLinPlackettLuce(t *testing.T) {
+	c := elo.NewWengLinCalculatorBuilder().Build()
+
+	r := c.NewRating()
+	n, err := c.Rate([][]elo.SkillRating{{r}, {r}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !skillEqual(n[0][0], 27.635, 8.066) {
+		t.Fail()
+		t.Logf("Expected winner %f/%f, got %f/%f\n", 27.635, 8.066, n[0][0].Mu, n[0][0].Sigma)
+	}
+	if !skillEqual(n[1][0], 22.365, 8.066) {
+		t.Fail()
+		t.Logf("Expected loser %f/%f, got %f/%f\n", 22.365, 8.066, n[1][0].Mu, n[1][0].Sigma)
+	}
+
+	// ranks may be given out of order
+	n, _ = c.Rate([][]elo.SkillRating{{r}, {r}, {r}}, []int{3, 1, 2})
+	if n[1][0].Mu <= n[2][0].Mu || n[2][0].Mu <= n[0][0].Mu {
+		t.Fail()
+		t.Logf("Ratings must follow finishing order, got %f, %f, %f\n", n[1][0].Mu, n[2][0].Mu, n[0][0].Mu)
+	}
+
+	// draw
+	n, _ = c.Rate([][]elo.SkillRating{{r}, {r}}, []int{1, 1})
+	if !almostEqual(n[0][0].Mu, 25) || !almostEqual(n[1][0].Mu, 25) {
+		t.Fail()
+		t.Logf("Equal players who draw must keep their skill, got %f and %f\n", n[0][0].Mu, n[1][0].Mu)
+	}
+}
+
+func TestWengLinModels(t *testing.T) {
+	models := []elo.WengLinModel{
+		elo.ModelPlackettLuce,
+		elo.ModelBradleyTerryFull,
+		elo.ModelBradleyTerryPart,
+		elo.ModelThurstoneMostellerFull,
+		elo.ModelThurstoneMostellerPart,
+	}
+
+	for _, model := range models {
+		c := elo.NewWengLinCalculatorBuilder().
+			WithModel(model).
+			WithMu(25).
+			WithSigma(25.0 / 3).
+			WithBeta(25.0 / 6).
+			WithKappa(0.0001).
+			WithDrawMargin(0.1).
+			WithSigma(-1). // will be ignored
+			Build()
+
+		r := c.NewRating()
+		teams := [][]elo.SkillRating{{r, r}, {r}, {r, r}}
+		n, err := c.Rate(teams, []int{1, 2, 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n[0][0].Mu <= 25 || n[2][0].Mu >= 25 {
+			t.Fail()
+			t.Logf("Model %d: first place must gain and last place must lose, got %f and %f\n",
+				model, n[0][0].Mu, n[2][0].Mu)
+		}
+		for _, team := range n {
+			for _, p := range team {
+				if p.Sigma >= r.Sigma {
+					t.Fail()
+					t.Logf("Model %d: uncertainty must shrink after a match, got %f\n", model, p.Sigma)
+				}
+			}
+		}
+	}
+
+	// in the full pairing models last place loses to both other teams,
+	// but in the partial models only to its neighbour
+	full := elo.NewWengLinCalculatorBuilder().WithModel(elo.ModelBradleyTerryFull).Build()
+	part := elo.NewWengLinCalculatorBuilder().WithModel(elo.ModelBradleyTerryPart).Build()
+	r := full.NewRating()
+	nf, _ := full.Rate([][]elo.SkillRating{{r}, {r}, {r}}, nil)
+	np, _ := part.Rate([][]elo.SkillRating{{r}, {r}, {r}}, nil)
+	if nf[2][0].Mu >= np[2][0].Mu {
+		t.Fail()
+		t.Logf("Full pairing must move last place further, got %f and %f\n", nf[2][0].Mu, np[2][0].Mu)
+	}
+
+	if _, err := full.Rate([][]elo.SkillRating{{r}}, nil); err != elo.ErrTooFewTeams {
+		t.Fail()
+		t.Logf("Expected %v, got %v\n", elo.ErrTooFewTeams, err)
+	}
+}
+
+func TestWengLinPredictWin(t *testing.T) {
+	c := elo.NewWengLinCalculatorBuilder().Build()
+
+	r := c.NewRating()
+	o := c.GetOdds([]elo.SkillRating{r}, []elo.SkillRating{r})
+	if !almostEqual(o.PlayerOneOdds, 0.5) || !almostEqual(o.PlayerTwoOdds, 0.5) {
+		t.Fail()
+		t.Logf("Expected even odds, got %f and %f\n", o.PlayerOneOdds, o.PlayerTwoOdds)
+	}
+
+	strong := elo.SkillRating{Mu: 30, Sigma: 5}
+	odds := c.PredictWin([][]elo.SkillRating{{strong}, {r}, {r}})
+	var sum float64
+	for _, p := range odds {
+		sum += p
+	}
+	if !almostEqual(sum, 1) {
+		t.Fail()
+		t.Logf("Win probabilities must sum to 1, got %f\n", sum)
+	}
+	if odds[0] <= odds[1] || !almostEqual(odds[1], odds[2]) {
+		t.Fail()
+		t.Logf("Unexpected win probabilities %v\n", odds)
+	}
+
+	p1 := &skillPlayer{25, 25.0 / 3}
+	p2 := &skillPlayer{25, 25.0 / 3}
+	if err := c.RatePlayers([][]elo.SkillPlayer{{p2}, {p1}}, []int{2, 1}); err != nil {
+		t.Fatal(err)
+	}
+	if !skillEqual(elo.SkillRating{Mu: p1.mu, Sigma: p1.sigma}, 27.635, 8.066) {
+		t.Fail()
+		t.Logf("Expected winner %f/%f, got %f/%f\n", 27.635, 8.066, p1.mu, p1.sigma)
+	}
+}
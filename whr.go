@@ -0,0 +1,255 @@
+package elo
+
+import (
+	"math"
+	"sort"
+)
+
+// A game for the Whole-History Rating solver.
+type WHRGame struct {
+	// The day the game was played on. Only the difference between days matters.
+	Day       int
+	PlayerOne string
+	PlayerTwo string
+	Outcome   MatchOutcome
+}
+
+// A player's rating on a single day they played on.
+type WHRRating struct {
+	Day int
+	Elo float64
+
+	// The standard deviation of the rating, in elo.
+	Uncertainty float64
+}
+
+// Solves for every player's rating on every day they played, using Rémi Coulom's
+// Whole-History Rating. Unlike incremental systems, the whole match history is used to
+// estimate each rating, so later games inform earlier ratings and the order games are
+// added in does not matter.
+//
+// Each player's rating is modelled as a Wiener process, so it may drift by about w2
+// elo squared per day, and every player is anchored by a virtual win and loss against
+// an opponent rated 0 on their first day.
+type WHR struct {
+	w2      float64
+	games   []WHRGame
+	players map[string]*whrPlayer
+	dirty   bool
+}
+
+type whrPlayer struct {
+	days []*whrDay
+}
+
+type whrDay struct {
+	day         int
+	r           float64
+	uncertainty float64
+	games       []whrGameRef
+}
+
+type whrGameRef struct {
+	opponent *whrDay
+	score    float64
+}
+
+// Returns a new solver where ratings may drift by about w2 elo squared per day.
+// Coulom suggests a w2 of around 14 for Go.
+func NewWHR(w2 float64) *WHR {
+	return &WHR{
+		w2:      w2,
+		players: make(map[string]*whrPlayer),
+	}
+}
+
+// Adds a game to the history. Games may be added in any order.
+func (w *WHR) AddGame(g WHRGame) {
+	if g.PlayerOne == g.PlayerTwo {
+		return
+	}
+	w.games = append(w.games, g)
+	w.dirty = true
+}
+
+// Runs up to the given number of Newton's method iterations, stopping early once no
+// rating changes by more than 0.001 elo. Returns the number of iterations run.
+func (w *WHR) Solve(iterations int) int {
+	w.build()
+	threshold := 0.001 * math.Ln10 / 400
+	// update players in a fixed order so that results are reproducible
+	names := w.Players()
+	for i := 0; i < iterations; i++ {
+		var change float64
+		for _, name := range names {
+			change = math.Max(change, w.newtonStep(w.players[name]))
+		}
+		if change < threshold {
+			w.uncertainties()
+			return i + 1
+		}
+	}
+	w.uncertainties()
+	return iterations
+}
+
+// Returns the player's rating on every day they played, in order. Returns nil if the
+// player has no games.
+func (w *WHR) Ratings(player string) []WHRRating {
+	p, ok := w.players[player]
+	if !ok {
+		return nil
+	}
+	ratings := make([]WHRRating, len(p.days))
+	for i, d := range p.days {
+		ratings[i] = WHRRating{
+			Day:         d.day,
+			Elo:         d.r * 400 / math.Ln10,
+			Uncertainty: d.uncertainty * 400 / math.Ln10,
+		}
+	}
+	return ratings
+}
+
+// Returns every player with at least one game, sorted by name.
+func (w *WHR) Players() []string {
+	w.build()
+	names := make([]string, 0, len(w.players))
+	for name := range w.players {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Rebuilds the player days from the game history, keeping any ratings already solved.
+func (w *WHR) build() {
+	if !w.dirty {
+		return
+	}
+	previous := make(map[string]map[int]float64)
+	for name, p := range w.players {
+		previous[name] = make(map[int]float64)
+		for _, d := range p.days {
+			previous[name][d.day] = d.r
+		}
+	}
+
+	w.players = make(map[string]*whrPlayer)
+	index := make(map[string]map[int]*whrDay)
+	day := func(name string, day int) *whrDay {
+		if index[name] == nil {
+			index[name] = make(map[int]*whrDay)
+			w.players[name] = new(whrPlayer)
+		}
+		d, ok := index[name][day]
+		if !ok {
+			d = &whrDay{day: day, r: previous[name][day]}
+			index[name][day] = d
+			w.players[name].days = append(w.players[name].days, d)
+		}
+		return d
+	}
+
+	for _, g := range w.games {
+		d1 := day(g.PlayerOne, g.Day)
+		d2 := day(g.PlayerTwo, g.Day)
+		S1, S2 := outcomeScores(g.Outcome)
+		d1.games = append(d1.games, whrGameRef{opponent: d2, score: S1})
+		d2.games = append(d2.games, whrGameRef{opponent: d1, score: S2})
+	}
+	for _, p := range w.players {
+		sort.Slice(p.days, func(i, j int) bool {
+			return p.days[i].day < p.days[j].day
+		})
+	}
+	w.dirty = false
+}
+
+// Returns the gradient and the diagonal and off-diagonal of the Hessian of the log
+// likelihood of the player's ratings.
+func (w *WHR) derivatives(p *whrPlayer) (grad, diag, off []float64) {
+	n := len(p.days)
+	grad = make([]float64, n)
+	diag = make([]float64, n)
+	off = make([]float64, n-1)
+
+	for i, d := range p.days {
+		gamma := math.Exp(d.r)
+		for _, g := range d.games {
+			og := math.Exp(g.opponent.r)
+			grad[i] += g.score - gamma/(gamma+og)
+			diag[i] -= gamma * og / ((gamma + og) * (gamma + og))
+		}
+	}
+
+	// prior: a virtual win and loss against an opponent rated 0
+	gamma := math.Exp(p.days[0].r)
+	grad[0] += 1 - 2*gamma/(gamma+1)
+	diag[0] -= 2 * gamma / ((gamma + 1) * (gamma + 1))
+
+	// wiener process between consecutive days
+	w2 := w.w2 * (math.Ln10 / 400) * (math.Ln10 / 400)
+	for i := 0; i < n-1; i++ {
+		sigma2 := float64(p.days[i+1].day-p.days[i].day) * w2
+		diff := p.days[i].r - p.days[i+1].r
+		grad[i] -= diff / sigma2
+		grad[i+1] += diff / sigma2
+		diag[i] -= 1 / sigma2
+		diag[i+1] -= 1 / sigma2
+		off[i] = 1 / sigma2
+	}
+	return grad, diag, off
+}
+
+// Updates all of a player's ratings with one step of Newton's method, returning the
+// largest change.
+func (w *WHR) newtonStep(p *whrPlayer) float64 {
+	grad, diag, off := w.derivatives(p)
+	n := len(grad)
+
+	// solve the tridiagonal system H x = grad using the Thomas algorithm
+	c := make([]float64, n)
+	x := make([]float64, n)
+	m := diag[0]
+	x[0] = grad[0] / m
+	for i := 1; i < n; i++ {
+		c[i-1] = off[i-1] / m
+		m = diag[i] - off[i-1]*c[i-1]
+		x[i] = (grad[i] - off[i-1]*x[i-1]) / m
+	}
+	for i := n - 2; i >= 0; i-- {
+		x[i] -= c[i] * x[i+1]
+	}
+
+	var change float64
+	for i, d := range p.days {
+		d.r -= x[i]
+		change = math.Max(change, math.Abs(x[i]))
+	}
+	return change
+}
+
+// Sets the uncertainty of every rating from the diagonal of the inverse Hessian.
+func (w *WHR) uncertainties() {
+	for _, p := range w.players {
+		_, diag, off := w.derivatives(p)
+		n := len(diag)
+
+		// forward and backward pivots of the tridiagonal Hessian
+		fwd := make([]float64, n)
+		bwd := make([]float64, n)
+		fwd[0] = diag[0]
+		for i := 1; i < n; i++ {
+			fwd[i] = diag[i] - off[i-1]*off[i-1]/fwd[i-1]
+		}
+		bwd[n-1] = diag[n-1]
+		for i := n - 2; i >= 0; i-- {
+			bwd[i] = diag[i] - off[i]*off[i]/bwd[i+1]
+		}
+		for i, d := range p.days {
+			variance := -1 / (fwd[i] + bwd[i] - diag[i])
+			d.uncertainty = math.Sqrt(variance)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package elo_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gabehf/go-elo"
+)
+
+func TestWHR(t *testing.T) {
+	w := elo.NewWHR(14)
+	w.AddGame(elo.WHRGame{Day: 1, PlayerOne: "a", PlayerTwo: "b", Outcome: elo.OutcomePlayerOneWin})
+
+	if n := w.Solve(50); n >= 50 {
+		t.Fail()
+		t.Log("Solver did not converge")
+	}
+
+	a := w.Ratings("a")
+	b := w.Ratings("b")
+	if len(a) != 1 || len(b) != 1 {
+		t.Fatalf("Expected one rating per player, got %d and %d\n", len(a), len(b))
+	}
+	if math.Abs(a[0].Elo+b[0].Elo) > 0.001 || a[0].Elo <= 0 {
+		t.Fail()
+		t.Logf("Expected symmetric ratings, got %f and %f\n", a[0].Elo, b[0].Elo)
+	}
+	// the winner's gamma solves g^3 - g^2 - 2 = 0
+	if math.Abs(a[0].Elo-91.732) > 0.01 {
+		t.Fail()
+		t.Logf("Expected rating %f, got %f\n", 91.732, a[0].Elo)
+	}
+	if a[0].Uncertainty <= 0 {
+		t.Fail()
+		t.Logf("Expected a positive uncertainty, got %f\n", a[0].Uncertainty)
+	}
+
+	if w.Ratings("c") != nil {
+		t.Fail()
+		t.Log("Expected no ratings for an unknown player")
+	}
+}
+
+func TestWHROrderIndependent(t *testing.T) {
+	games := []elo.WHRGame{
+		{Day: 1, PlayerOne: "a", PlayerTwo: "b", Outcome: elo.OutcomePlayerOneWin},
+		{Day: 1, PlayerOne: "b", PlayerTwo: "c", Outcome: elo.OutcomeDraw},
+		{Day: 5, PlayerOne: "a", PlayerTwo: "c", Outcome: elo.OutcomePlayerTwoWin},
+		{Day: 9, PlayerOne: "c", PlayerTwo: "b", Outcome: elo.OutcomePlayerOneWin},
+		{Day: 9, PlayerOne: "a", PlayerTwo: "b", Outcome: elo.OutcomePlayerOneWin},
+		{Day: 30, PlayerOne: "b", PlayerTwo: "a", Outcome: elo.OutcomePlayerOneWin},
+	}
+
+	w1 := elo.NewWHR(14)
+	w2 := elo.NewWHR(14)
+	for i := range games {
+		w1.AddGame(games[i])
+		w2.AddGame(games[len(games)-1-i])
+	}
+	w1.Solve(100)
+	w2.Solve(100)
+
+	players := w1.Players()
+	if len(players) != 3 {
+		t.Fatalf("Expected 3 players, got %v\n", players)
+	}
+	for _, p := range players {
+		r1, r2 := w1.Ratings(p), w2.Ratings(p)
+		if len(r1) != len(r2) {
+			t.Fatalf("Expected the same days for %s, got %d and %d\n", p, len(r1), len(r2))
+		}
+		for i := range r1 {
+			if r1[i].Day != r2[i].Day || math.Abs(r1[i].Elo-r2[i].Elo) > 0.01 {
+				t.Fail()
+				t.Logf("Expected %s on day %d to be %f, got %f\n", p, r1[i].Day, r1[i].Elo, r2[i].Elo)
+			}
+		}
+	}
+
+	// a's rating drifts down over time as later results come in
+	a := w1.Ratings("a")
+	if a[0].Day != 1 || a[len(a)-1].Day != 30 {
+		t.Fail()
+		t.Logf("Expected days in order, got %v\n", a)
+	}
+	if a[len(a)-1].Elo >= a[0].Elo {
+		t.Fail()
+		t.Logf("Expected a's rating to fall, got %f to %f\n", a[0].Elo, a[len(a)-1].Elo)
+	}
+}
+
+func TestWHRUncertainty(t *testing.T) {
+	w := elo.NewWHR(14)
+	for day := 0; day < 20; day++ {
+		w.AddGame(elo.WHRGame{Day: day, PlayerOne: "a", PlayerTwo: "b", Outcome: elo.OutcomePlayerOneWin})
+		w.AddGame(elo.WHRGame{Day: day, PlayerOne: "a", PlayerTwo: "b", Outcome: elo.OutcomePlayerTwoWin})
+	}
+	w.AddGame(elo.WHRGame{Day: 0, PlayerOne: "a", PlayerTwo: "c", Outcome: elo.OutcomePlayerOneWin})
+	w.AddGame(elo.WHRGame{Day: 0, PlayerOne: "a", PlayerTwo: "a", Outcome: elo.OutcomePlayerOneWin}) // ignored
+	w.Solve(100)
+
+	a := w.Ratings("a")
+	c := w.Ratings("c")
+	if a[10].Uncertainty >= c[0].Uncertainty {
+		t.Fail()
+		t.Logf("A player with more games must be more certain, got %f and %f\n", a[10].Uncertainty, c[0].Uncertainty)
+	}
+}